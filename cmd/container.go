@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/d-leme/tradew-trades/pkg/core"
 	"github.com/d-leme/tradew-trades/pkg/trades"
 	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
@@ -15,6 +17,9 @@ import (
 	"google.golang.org/grpc"
 )
 
+// eventProducer name set in the envelope of every published event
+const eventProducer = "tradew-trades"
+
 // Container ...
 type Container struct {
 	Settings *core.Settings
@@ -23,12 +28,51 @@ type Container struct {
 
 	MongoClient *mongo.Client
 
+	AWSSession     *session.Session
+	Resolver       *core.Resolver
+	Broker         core.Broker
+	Publisher      core.Publisher
+	EventRegistry  *core.EventRegistry
+	EventPublisher *core.EventPublisher
+
 	InventoryServiceConnection *grpc.ClientConn
 	InventoryService           inventory.Service
 
+	ReputationRepository trades.ReputationRepository
+	ReputationService    trades.ReputationService
+	ReputationController trades.ReputationController
+
+	BlockRepository trades.BlockRepository
+	BlockService    trades.BlockService
+	BlockController trades.BlockController
+
 	TradeRepository trades.Repository
 	TradeService    trades.Service
 	TradeController trades.Controller
+
+	InventoryConsumer *trades.InventoryConsumer
+
+	FeedbackRepository trades.FeedbackRepository
+	FeedbackService    trades.FeedbackService
+	FeedbackController trades.FeedbackController
+
+	BatchService    trades.BatchService
+	BatchController trades.BatchController
+
+	StatsService    trades.StatsService
+	StatsController trades.StatsController
+
+	MultiPartyRepository trades.MultiPartyRepository
+	MultiPartyService    trades.MultiPartyService
+	MultiPartyController trades.MultiPartyController
+
+	AuctionRepository trades.AuctionRepository
+	AuctionService    trades.AuctionService
+	AuctionController trades.AuctionController
+
+	SuggestionRepository trades.SuggestionRepository
+	MatchingService      trades.MatchingService
+	MatchingController   trades.MatchingController
 }
 
 // NewContainer creates new instace of Container
@@ -42,16 +86,113 @@ func NewContainer(settings *core.Settings) *Container {
 
 	container.Authenticate = core.NewAuthenticate(settings.JWT.Secret)
 
+	if settings.AWS != nil {
+		container.AWSSession = connectAWS(settings.AWS)
+	}
+
+	registry, err := core.NewEventRegistry(trades.EventSchemas())
+	if err != nil {
+		logrus.
+			WithError(err).
+			Fatal("error loading event schemas")
+	}
+
+	container.EventRegistry = registry
+
+	if container.AWSSession != nil {
+		container.Resolver = core.NewResolver(container.AWSSession, settings.AWS.AccountID)
+	}
+
+	container.Broker = newBroker(settings.Broker, container.AWSSession, container.Resolver)
+	if container.Broker != nil {
+		container.Publisher = container.Broker.Publisher()
+		container.EventPublisher = core.NewEventPublisher(container.Publisher, container.EventRegistry, eventProducer)
+	}
+
 	// GRPC
 	container.InventoryServiceConnection = connectGRPC(settings.InventoryService)
 	container.InventoryService = proto.NewService(
 		proto.NewInventoryServiceClient(container.InventoryServiceConnection),
 	)
 
+	// Reputation
+	container.ReputationRepository = mongodb.NewReputationRepository(container.MongoClient, settings.MongoDB.Database)
+	container.ReputationService = trades.NewReputationService(container.ReputationRepository)
+	container.ReputationController = trades.NewReputationController(container.Authenticate, container.ReputationService)
+
+	// Blocks
+	container.BlockRepository = mongodb.NewBlockRepository(container.MongoClient, settings.MongoDB.Database)
+	container.BlockService = trades.NewBlockService(container.BlockRepository)
+	container.BlockController = trades.NewBlockController(container.Authenticate, container.BlockService)
+
 	// Trades
 	container.TradeRepository = mongodb.NewRepository(container.MongoClient, settings.MongoDB.Database)
-	container.TradeService = trades.NewService(container.TradeRepository, container.InventoryService)
+
+	rules := trades.NewDefaultRuleEngine(container.TradeRepository, settings.TradeRules).
+		With(trades.NotBlockedRule(container.BlockRepository))
+	if settings.Reputation != nil && settings.Reputation.MinScore > 0 {
+		rules = rules.With(trades.MinReputationRule(container.ReputationRepository, settings.Reputation.MinScore, settings.Reputation.MinTrades))
+	}
+
+	tradeServiceOptions := []trades.ServiceOption{
+		trades.WithRuleEngine(rules),
+		trades.WithTransitionHandler(container.ReputationService),
+		trades.WithBlockRepository(container.BlockRepository),
+	}
+
+	if container.EventPublisher != nil {
+		tradeServiceOptions = append(tradeServiceOptions, trades.WithProducer(container.EventPublisher, settings.Topics))
+	}
+
+	container.TradeService = trades.NewService(
+		container.TradeRepository,
+		container.InventoryService,
+		tradeServiceOptions...,
+	)
 	container.TradeController = trades.NewController(container.Authenticate, container.TradeService)
+	container.InventoryConsumer = trades.NewInventoryConsumer(
+		container.TradeService,
+		settings.Topics,
+		container.EventRegistry,
+		trades.WithInventoryInbox(newInbox(container.MongoClient, settings)),
+	)
+
+	container.FeedbackRepository = mongodb.NewFeedbackRepository(container.MongoClient, settings.MongoDB.Database)
+	container.FeedbackService = trades.NewFeedbackService(container.FeedbackRepository, container.TradeRepository)
+	container.FeedbackController = trades.NewFeedbackController(container.Authenticate, container.FeedbackService)
+
+	container.BatchService = trades.NewBatchService(container.TradeService, settings.Batch)
+	container.BatchController = trades.NewBatchController(container.Authenticate, container.BatchService)
+
+	statsSettings := settings.Stats
+	if statsSettings == nil {
+		statsSettings = &core.Stats{}
+	}
+
+	container.StatsService = trades.NewStatsService(container.TradeRepository, statsSettings)
+	container.StatsController = trades.NewStatsController(container.Authenticate, container.StatsService, statsSettings.CacheTTL)
+
+	container.MultiPartyRepository = mongodb.NewMultiPartyRepository(container.MongoClient, settings.MongoDB.Database)
+	container.MultiPartyService = trades.NewMultiPartyService(container.MultiPartyRepository, container.InventoryService)
+	container.MultiPartyController = trades.NewMultiPartyController(container.Authenticate, container.MultiPartyService)
+
+	container.AuctionRepository = mongodb.NewAuctionRepository(container.MongoClient, settings.MongoDB.Database)
+	container.AuctionService = trades.NewAuctionService(
+		container.AuctionRepository,
+		container.TradeService,
+		container.InventoryService,
+		settings.Auctions,
+		trades.WithAuctionTransitionHandler(container.ReputationService),
+	)
+	container.AuctionController = trades.NewAuctionController(container.Authenticate, container.AuctionService)
+
+	container.SuggestionRepository = mongodb.NewSuggestionRepository(container.MongoClient, settings.MongoDB.Database)
+	container.MatchingService = trades.NewMatchingService(
+		container.TradeRepository,
+		container.SuggestionRepository,
+		settings.Matching,
+	)
+	container.MatchingController = trades.NewMatchingController(container.Authenticate, container.MatchingService)
 
 	return container
 }
@@ -60,15 +201,62 @@ func NewContainer(settings *core.Settings) *Container {
 func (c *Container) Controllers() []core.Controller {
 	return []core.Controller{
 		&c.TradeController,
+		&c.BatchController,
+		&c.StatsController,
+		&c.MultiPartyController,
+		&c.AuctionController,
+		&c.MatchingController,
+		&c.ReputationController,
+		&c.FeedbackController,
+		&c.BlockController,
 	}
 }
 
+// Subscriptions lists every topic the service consumes
+func (c *Container) Subscriptions() []*core.Subscription {
+	return c.InventoryConsumer.Subscriptions()
+}
+
+// Subscribers creates the consumers of every subscribed topic
+func (c *Container) Subscribers() []core.Subscriber {
+	subscribers := []core.Subscriber{}
+
+	if c.Broker == nil {
+		return subscribers
+	}
+
+	for _, subscription := range c.Subscriptions() {
+		subscribers = append(subscribers, c.Broker.Subscriber(subscription))
+	}
+
+	return subscribers
+}
+
 // Close terminates every opened resource
 func (c *Container) Close() {
 	c.MongoClient.Disconnect(context.Background())
 	c.InventoryServiceConnection.Close()
 }
 
+// newInbox the inbox is only created when configured, without it redelivered
+// messages are handled again
+func newInbox(client *mongo.Client, settings *core.Settings) *core.Inbox {
+	if settings.Inbox == nil {
+		return nil
+	}
+
+	opts := []core.InboxOption{
+		core.WithInboxTTL(settings.Inbox.TTL),
+		core.WithInboxLease(settings.Inbox.Lease),
+	}
+
+	if settings.Inbox.Transactional {
+		opts = append(opts, core.WithInboxTransaction())
+	}
+
+	return core.NewInbox(client, settings.MongoDB.Database, opts...)
+}
+
 func connectMongoDB(conf *core.MongoDBConfig) *mongo.Client {
 	client, err := mongo.NewClient(options.Client().ApplyURI(conf.ConnectionString))
 
@@ -91,6 +279,45 @@ func connectMongoDB(conf *core.MongoDBConfig) *mongo.Client {
 	return client
 }
 
+func connectAWS(conf *core.AWS) *session.Session {
+	config := &aws.Config{Region: aws.String(conf.Region)}
+
+	if conf.Endpoint != "" {
+		config.Endpoint = aws.String(conf.Endpoint)
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		logrus.
+			WithError(err).
+			Fatal("error creating AWS session")
+	}
+
+	return sess
+}
+
+// newBroker defaults to aws when a session exists, nil means events are not published
+func newBroker(conf *core.BrokerConfig, sess *session.Session, resolver *core.Resolver) core.Broker {
+	brokerType := core.BrokerAWS
+	if conf != nil && conf.Type != "" {
+		brokerType = conf.Type
+	}
+
+	switch brokerType {
+	case core.BrokerMemory:
+		return core.NewInMemoryBroker(conf)
+	case core.BrokerAWS:
+		if sess == nil {
+			return nil
+		}
+
+		return core.NewAWSBroker(sess, resolver, conf)
+	default:
+		logrus.Fatalf("unknown broker type %s", brokerType)
+		return nil
+	}
+}
+
 func connectGRPC(srv *core.GRPCService) *grpc.ClientConn {
 	conn, err := grpc.Dial(srv.URL, grpc.WithInsecure())
 	if err != nil {
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DLQList is a cmd to print the dead letters of a subscriber
+func DLQList(command *cobra.Command, args []string) {
+	max, _ := command.Flags().GetInt("max")
+
+	withDeadLetterQueue(command, args[0], func(ctx context.Context, dlq core.DeadLetterQueue) error {
+		messages, err := dlq.List(ctx, max)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(command.OutOrStdout(), 0, 4, 2, ' ', 0)
+
+		fmt.Fprintln(w, "ID\tRECEIVE COUNT\tCORRELATION ID\tBODY")
+		for _, message := range messages {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", message.ID, message.ReceiveCount, message.CorrelationID, message.Body)
+		}
+
+		return w.Flush()
+	})
+}
+
+// DLQReplay is a cmd to send the dead letters of a subscriber back to its queue or topic
+func DLQReplay(command *cobra.Command, args []string) {
+	ids, _ := command.Flags().GetStringSlice("id")
+	all, _ := command.Flags().GetBool("all")
+	target, _ := command.Flags().GetString("to")
+
+	// replaying everything has to be asked for explicitly
+	if len(ids) == 0 && !all {
+		logrus.Fatal("either --id or --all is required")
+		return
+	}
+
+	withDeadLetterQueue(command, args[0], func(ctx context.Context, dlq core.DeadLetterQueue) error {
+		replayed, err := dlq.Replay(ctx, &core.ReplayRequest{MessageIDs: ids, Target: target})
+
+		fmt.Fprintf(command.OutOrStdout(), "%d messages replayed to the %s\n", replayed, target)
+
+		return err
+	})
+}
+
+// DLQPurge is a cmd to delete every dead letter of a subscriber
+func DLQPurge(command *cobra.Command, args []string) {
+	withDeadLetterQueue(command, args[0], func(ctx context.Context, dlq core.DeadLetterQueue) error {
+		return dlq.Purge(ctx)
+	})
+}
+
+func withDeadLetterQueue(command *cobra.Command, subscriberID string, run func(context.Context, core.DeadLetterQueue) error) {
+
+	settings := new(core.Settings)
+
+	if err := core.FromYAML(command.Flag("settings").Value.String(), settings); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("unable to parse settings, shutting down...")
+		return
+	}
+
+	container := NewContainer(settings)
+
+	defer container.Close()
+
+	if container.Broker == nil {
+		logrus.Fatal("no message broker configured, shutting down...")
+		return
+	}
+
+	var subscription *core.Subscription
+
+	for _, s := range container.Subscriptions() {
+		if s.SubscriberID == subscriberID {
+			subscription = s
+			break
+		}
+	}
+
+	if subscription == nil {
+		logrus.Fatalf("unknown subscriber %s", subscriberID)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, container.Broker.DeadLetterQueue(subscription)); err != nil {
+		logrus.
+			WithError(err).
+			WithField("subscriber_id", subscriberID).
+			Error("error running dead letter queue command")
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Jobs is a cmd to run the periodic background jobs
+func Jobs(command *cobra.Command, args []string) {
+
+	settings := new(core.Settings)
+
+	if err := core.FromYAML(command.Flag("settings").Value.String(), settings); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("unable to parse settings, shutting down...")
+		return
+	}
+
+	container := NewContainer(settings)
+
+	defer container.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wg := new(sync.WaitGroup)
+
+	auctions := settings.Auctions
+	if auctions == nil {
+		auctions = &core.Auctions{}
+	}
+
+	matching := settings.Matching
+	if matching == nil {
+		matching = &core.Matching{}
+	}
+
+	runPeriodically(ctx, wg, "close-auctions", auctions.CloseInterval, container.AuctionService.CloseExpired)
+	runPeriodically(ctx, wg, "match-suggestions", matching.Interval, container.MatchingService.Run)
+
+	logrus.Info("jobs started")
+
+	wg.Wait()
+
+	logrus.Info("jobs stopped")
+}
+
+func runPeriodically(ctx context.Context, wg *sync.WaitGroup, name string, interval time.Duration, job func(context.Context) error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(ctx); err != nil {
+				logrus.WithError(err).WithField("job", name).Error("error running job")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
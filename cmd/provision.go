@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Provision is a cmd to create the topics, queues and subscriptions of a manifest
+func Provision(command *cobra.Command, args []string) {
+
+	settings := new(core.Settings)
+
+	if err := core.FromYAML(command.Flag("settings").Value.String(), settings); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("unable to parse settings, shutting down...")
+		return
+	}
+
+	if settings.AWS == nil {
+		logrus.Fatal("no aws settings, shutting down...")
+		return
+	}
+
+	manifest := new(core.Manifest)
+
+	if err := core.FromYAML(command.Flag("manifest").Value.String(), manifest); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("unable to parse manifest, shutting down...")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resolver := core.NewResolver(connectAWS(settings.AWS), settings.AWS.AccountID)
+
+	if err := core.NewProvisioner(resolver).Provision(ctx, manifest); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("error provisioning message broker")
+		return
+	}
+
+	logrus.
+		WithField("topics", len(manifest.Topics)).
+		WithField("subscriptions", len(manifest.Subscriptions)).
+		Info("message broker provisioned")
+}
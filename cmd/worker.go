@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Worker is a cmd to run the message broker consumers
+func Worker(command *cobra.Command, args []string) {
+
+	settings := new(core.Settings)
+
+	if err := core.FromYAML(command.Flag("settings").Value.String(), settings); err != nil {
+		logrus.
+			WithError(err).
+			Fatal("unable to parse settings, shutting down...")
+		return
+	}
+
+	container := NewContainer(settings)
+
+	defer container.Close()
+
+	if container.Broker == nil {
+		logrus.Fatal("no message broker configured, shutting down...")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wg := new(sync.WaitGroup)
+
+	for _, subscriber := range container.Subscribers() {
+		wg.Add(1)
+
+		go func(subscriber core.Subscriber) {
+			defer wg.Done()
+
+			if err := subscriber.Run(ctx); err != nil {
+				logrus.WithError(err).Error("error running subscriber")
+				stop()
+			}
+		}(subscriber)
+	}
+
+	logrus.Info("worker started")
+
+	wg.Wait()
+
+	logrus.Info("worker stopped")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/d-leme/tradew-trades/cmd"
+	"github.com/d-leme/tradew-trades/pkg/core"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -21,8 +22,59 @@ func main() {
 		Run:   cmd.Server,
 	}
 
+	jobs := &cobra.Command{
+		Use:   "jobs",
+		Short: "Starts periodic background jobs",
+		Run:   cmd.Jobs,
+	}
+
+	worker := &cobra.Command{
+		Use:   "worker",
+		Short: "Starts message broker consumers",
+		Run:   cmd.Worker,
+	}
+
+	dlq := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspects and reprocesses the dead letter queue of a subscriber",
+	}
+
+	dlqList := &cobra.Command{
+		Use:   "list <subscriber>",
+		Short: "Lists dead letters with their decoded bodies and receive counts",
+		Args:  cobra.ExactArgs(1),
+		Run:   cmd.DLQList,
+	}
+	dlqList.Flags().Int("max", 100, "maximum number of messages to list")
+
+	dlqReplay := &cobra.Command{
+		Use:   "replay <subscriber>",
+		Short: "Replays dead letters to the queue of the subscriber or to its topic",
+		Args:  cobra.ExactArgs(1),
+		Run:   cmd.DLQReplay,
+	}
+	dlqReplay.Flags().StringSlice("id", nil, "ids of the messages to replay")
+	dlqReplay.Flags().Bool("all", false, "replay every message")
+	dlqReplay.Flags().String("to", core.ReplayToQueue, "replay target, queue or topic")
+
+	dlqPurge := &cobra.Command{
+		Use:   "purge <subscriber>",
+		Short: "Deletes every dead letter",
+		Args:  cobra.ExactArgs(1),
+		Run:   cmd.DLQPurge,
+	}
+
+	dlq.AddCommand(dlqList, dlqReplay, dlqPurge)
+
+	provision := &cobra.Command{
+		Use:   "provision",
+		Short: "Creates the topics, queues and subscriptions of a manifest",
+		Run:   cmd.Provision,
+	}
+	provision.Flags().String("manifest", "./provision.yml", "path to the provisioning manifest")
+
 	root.PersistentFlags().String("settings", "./settings.yml", "path to settings.yaml config file")
-	root.AddCommand(api)
+	root.AddCommand(api, jobs, worker, dlq, provision)
 
 	root.Execute()
 }
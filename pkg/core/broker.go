@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+const (
+	// BrokerAWS publishes to SNS topics and consumes from SQS queues
+	BrokerAWS = "aws"
+
+	// BrokerMemory delivers messages through channels inside the process
+	BrokerMemory = "memory"
+
+	// FIFOSuffix topics and queues named with it are FIFO
+	FIFOSuffix = ".fifo"
+
+	defaultMaxRetries = 5
+)
+
+// Publisher publishes messages to a topic
+type Publisher interface {
+	Publish(topicID string, data interface{}) (string, error)
+	PublishWithAttributes(topicID string, data interface{}, attributes map[string]string) (string, error)
+	PublishWithOptions(topicID string, data interface{}, opts *PublishOptions) (string, error)
+}
+
+// PublishOptions the group and deduplication ids are only used by FIFO topics,
+// they deliver the messages of a group in order and each deduplication id once
+type PublishOptions struct {
+	Attributes      map[string]string
+	GroupID         string
+	DeduplicationID string
+}
+
+// Subscriber consumes the messages of a topic until the context is cancelled
+type Subscriber interface {
+	Run(ctx context.Context) error
+}
+
+// Subscription describes what a subscriber consumes and how often a message
+// is retried before it is moved to the dead letter queue, no filter policy
+// delivers every message of the topic
+type Subscription struct {
+	SubscriberID string
+	TopicID      string
+	Handler      Handler
+	Middlewares  []Middleware
+	MaxRetries   int
+	FilterPolicy FilterPolicy
+}
+
+// Broker creates the publishers and subscribers of one message broker
+type Broker interface {
+	Publisher() Publisher
+	Subscriber(subscription *Subscription) Subscriber
+	DeadLetterQueue(subscription *Subscription) DeadLetterQueue
+}
+
+// AWSBroker publishes to SNS topics and consumes from SQS queues subscribed to them
+type AWSBroker struct {
+	session  *session.Session
+	resolver *Resolver
+	producer *MessageBrokerProducer
+	conf     *BrokerConfig
+}
+
+// NewAWSBroker the resolver is shared by the publisher and every subscriber,
+// the workers, max messages, wait time and visibility timeout of conf are
+// applied to every subscriber
+func NewAWSBroker(s *session.Session, resolver *Resolver, conf *BrokerConfig) *AWSBroker {
+	if conf == nil {
+		conf = &BrokerConfig{}
+	}
+
+	return &AWSBroker{
+		session:  s,
+		resolver: resolver,
+		producer: NewMessageBrokerProducer(s, WithProducerResolver(resolver)),
+		conf:     conf,
+	}
+}
+
+// Publisher ...
+func (b *AWSBroker) Publisher() Publisher {
+	return b.producer
+}
+
+// Subscriber ...
+func (b *AWSBroker) Subscriber(subscription *Subscription) Subscriber {
+	return NewMessageBrokerSubscriber(
+		WithSessionSQS(b.session),
+		WithResolver(b.resolver),
+		WithSubscription(subscription),
+		WithWorkers(b.conf.Workers),
+		WithMaxMessages(b.conf.MaxMessages),
+		WithWaitTime(b.conf.WaitTime),
+		WithVisibilityTimeout(b.conf.VisibilityTimeout),
+	)
+}
+
+// DeadLetterQueue ...
+func (b *AWSBroker) DeadLetterQueue(subscription *Subscription) DeadLetterQueue {
+	return NewSQSDeadLetterQueue(sqs.New(b.session), b.producer, subscription.SubscriberID, subscription.TopicID)
+}
+
+// IsFIFO reports whether the topic or queue delivers the messages of a group in order
+func IsFIFO(name string) bool {
+	return strings.HasSuffix(name, FIFOSuffix)
+}
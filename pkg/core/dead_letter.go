@@ -0,0 +1,296 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ReplayToQueue sends the messages back to the queue of the subscriber only
+	ReplayToQueue = "queue"
+
+	// ReplayToTopic publishes the messages again to every subscriber of the topic
+	ReplayToTopic = "topic"
+
+	dlqWaitTime          = 2 * time.Second
+	dlqVisibilityTimeout = 5 * time.Minute
+	maxBatchEntries      = 10
+)
+
+// ReplayRequest selects the dead letters to replay, no message ids replays all of them
+type ReplayRequest struct {
+	MessageIDs []string
+	Target     string
+}
+
+// DeadLetterQueue inspects and reprocesses the messages a subscriber gave up on
+type DeadLetterQueue interface {
+	// List returns up to max messages without removing them
+	List(ctx context.Context, max int) ([]*Message, error)
+
+	// Replay moves the selected messages out of the dead letter queue and
+	// returns how many were replayed
+	Replay(ctx context.Context, req *ReplayRequest) (int, error)
+
+	// Purge deletes every message
+	Purge(ctx context.Context) error
+}
+
+// DeadLetterQueueName name of the queue the redrive policy of a subscriber
+// targets, the dead letter queue of a FIFO queue is FIFO as well
+func DeadLetterQueueName(subscriberID string) string {
+	if IsFIFO(subscriberID) {
+		return fmt.Sprintf("%s_dlq%s", strings.TrimSuffix(subscriberID, FIFOSuffix), FIFOSuffix)
+	}
+
+	return fmt.Sprintf("%s_dlq", subscriberID)
+}
+
+// SQSDeadLetterQueue dead letter queue created by MessageBrokerSubscriber
+type SQSDeadLetterQueue struct {
+	sqsSvc       sqsiface.SQSAPI
+	publisher    Publisher
+	subscriberID string
+	topicID      string
+}
+
+// NewSQSDeadLetterQueue the publisher and topic are used to replay to the topic
+func NewSQSDeadLetterQueue(sqsSvc sqsiface.SQSAPI, publisher Publisher, subscriberID, topicID string) *SQSDeadLetterQueue {
+	return &SQSDeadLetterQueue{
+		sqsSvc:       sqsSvc,
+		publisher:    publisher,
+		subscriberID: subscriberID,
+		topicID:      topicID,
+	}
+}
+
+// List receiving is the only way to read sqs messages, they are hidden while
+// listed and made visible again once done
+func (q *SQSDeadLetterQueue) List(ctx context.Context, max int) ([]*Message, error) {
+	dlqURL, err := q.queueURL(ctx, DeadLetterQueueName(q.subscriberID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Message{}
+	received := []*sqs.Message{}
+
+	defer func() { q.release(dlqURL, received) }()
+
+	for len(result) < max {
+		messages, err := q.receive(ctx, dlqURL, int64(max-len(result)))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, mess := range messages {
+			received = append(received, mess)
+			result = append(result, toDeadLetter(mess))
+		}
+	}
+
+	return result, nil
+}
+
+// Replay sends the original body back to the queue or publishes the
+// decoded message to the topic, it is deleted only after being replayed
+func (q *SQSDeadLetterQueue) Replay(ctx context.Context, req *ReplayRequest) (int, error) {
+	dlqURL, err := q.queueURL(ctx, DeadLetterQueueName(q.subscriberID))
+	if err != nil {
+		return 0, err
+	}
+
+	var sourceURL *string
+
+	switch req.Target {
+	case ReplayToQueue:
+		if sourceURL, err = q.queueURL(ctx, q.subscriberID); err != nil {
+			return 0, err
+		}
+	case ReplayToTopic:
+		if q.publisher == nil || q.topicID == "" {
+			return 0, fmt.Errorf("subscriber %s has no topic to replay to", q.subscriberID)
+		}
+	default:
+		return 0, fmt.Errorf("unknown replay target %s", req.Target)
+	}
+
+	selected := map[string]bool{}
+	for _, id := range req.MessageIDs {
+		selected[id] = true
+	}
+
+	replayed := 0
+	skipped := []*sqs.Message{}
+
+	defer func() { q.release(dlqURL, skipped) }()
+
+	for len(selected) == 0 || replayed < len(selected) {
+		messages, err := q.receive(ctx, dlqURL, maxReceiveMessages)
+		if err != nil {
+			return replayed, err
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		for i, mess := range messages {
+			if len(selected) > 0 && !selected[aws.StringValue(mess.MessageId)] {
+				skipped = append(skipped, mess)
+				continue
+			}
+
+			if err := q.replay(ctx, sourceURL, mess); err != nil {
+				skipped = append(skipped, messages[i:]...)
+				return replayed, err
+			}
+
+			_, err := q.sqsSvc.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      dlqURL,
+				ReceiptHandle: mess.ReceiptHandle,
+			})
+
+			if err != nil {
+				skipped = append(skipped, messages[i+1:]...)
+				return replayed, err
+			}
+
+			replayed++
+		}
+	}
+
+	return replayed, nil
+}
+
+// Purge ...
+func (q *SQSDeadLetterQueue) Purge(ctx context.Context) error {
+	dlqURL, err := q.queueURL(ctx, DeadLetterQueueName(q.subscriberID))
+	if err != nil {
+		return err
+	}
+
+	_, err = q.sqsSvc.PurgeQueueWithContext(ctx, &sqs.PurgeQueueInput{QueueUrl: dlqURL})
+
+	return err
+}
+
+func (q *SQSDeadLetterQueue) replay(ctx context.Context, sourceURL *string, mess *sqs.Message) error {
+	if sourceURL != nil {
+		input := &sqs.SendMessageInput{
+			QueueUrl:          sourceURL,
+			MessageBody:       mess.Body,
+			MessageAttributes: mess.MessageAttributes,
+		}
+
+		// the dead letter id deduplicates the replay, not the original message
+		if IsFIFO(q.subscriberID) {
+			input.MessageGroupId = mess.Attributes[sqs.MessageSystemAttributeNameMessageGroupId]
+			input.MessageDeduplicationId = mess.MessageId
+		}
+
+		_, err := q.sqsSvc.SendMessageWithContext(ctx, input)
+
+		return err
+	}
+
+	message := toDeadLetter(mess)
+
+	_, err := q.publisher.PublishWithOptions(q.topicID, json.RawMessage(message.Body), &PublishOptions{
+		Attributes:      message.Attributes,
+		GroupID:         message.GroupID,
+		DeduplicationID: message.ID,
+	})
+
+	return err
+}
+
+func (q *SQSDeadLetterQueue) receive(ctx context.Context, queueURL *string, max int64) ([]*sqs.Message, error) {
+	if max > maxReceiveMessages {
+		max = maxReceiveMessages
+	}
+
+	output, err := q.sqsSvc.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl: queueURL,
+		AttributeNames: aws.StringSlice([]string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount,
+			sqs.MessageSystemAttributeNameMessageGroupId,
+		}),
+		MessageAttributeNames: aws.StringSlice([]string{"All"}),
+		MaxNumberOfMessages:   aws.Int64(max),
+		WaitTimeSeconds:       aws.Int64(int64(dlqWaitTime.Seconds())),
+		VisibilityTimeout:     aws.Int64(int64(dlqVisibilityTimeout.Seconds())),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Messages, nil
+}
+
+// release makes the messages visible again, it runs after the context of the
+// command may be done so it uses its own
+func (q *SQSDeadLetterQueue) release(queueURL *string, messages []*sqs.Message) {
+	for start := 0; start < len(messages); start += maxBatchEntries {
+		end := start + maxBatchEntries
+		if end > len(messages) {
+			end = len(messages)
+		}
+
+		entries := []*sqs.ChangeMessageVisibilityBatchRequestEntry{}
+		for i, mess := range messages[start:end] {
+			entries = append(entries, &sqs.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i)),
+				ReceiptHandle:     mess.ReceiptHandle,
+				VisibilityTimeout: aws.Int64(0),
+			})
+		}
+
+		_, err := q.sqsSvc.ChangeMessageVisibilityBatch(&sqs.ChangeMessageVisibilityBatchInput{
+			QueueUrl: queueURL,
+			Entries:  entries,
+		})
+
+		if err != nil {
+			logrus.WithError(err).
+				Errorf("error releasing dead letters %s", q.subscriberID)
+		}
+	}
+}
+
+func (q *SQSDeadLetterQueue) queueURL(ctx context.Context, name string) (*string, error) {
+	output, err := q.sqsSvc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if err != nil {
+		return nil, fmt.Errorf("error getting queue %s: %w", name, err)
+	}
+
+	return output.QueueUrl, nil
+}
+
+// toDeadLetter keeps the sqs message id, it is the one used to select messages to replay
+func toDeadLetter(mess *sqs.Message) *Message {
+	message := decodeSNSMessage(aws.StringValue(mess.MessageId), []byte(aws.StringValue(mess.Body)))
+	message.ID = aws.StringValue(mess.MessageId)
+	message.ReceiveCount, _ = strconv.Atoi(aws.StringValue(mess.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	message.GroupID = aws.StringValue(mess.Attributes[sqs.MessageSystemAttributeNameMessageGroupId])
+
+	for name, attribute := range mess.MessageAttributes {
+		message.Attributes[name] = aws.StringValue(attribute.StringValue)
+	}
+
+	return message
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeDLQ hands out every queued message once, like messages hidden by the
+// visibility timeout, and records what was sent, deleted and released
+type fakeDLQ struct {
+	sqsiface.SQSAPI
+	messages []*sqs.Message
+	sent     map[string][]string
+	deleted  []string
+	released []string
+}
+
+func (f *fakeDLQ) GetQueueUrlWithContext(ctx aws.Context, input *sqs.GetQueueUrlInput, opts ...request.Option) (*sqs.GetQueueUrlOutput, error) {
+	return &sqs.GetQueueUrlOutput{QueueUrl: input.QueueName}, nil
+}
+
+func (f *fakeDLQ) ReceiveMessageWithContext(ctx aws.Context, input *sqs.ReceiveMessageInput, opts ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	count := int(*input.MaxNumberOfMessages)
+	if count > len(f.messages) {
+		count = len(f.messages)
+	}
+
+	messages := f.messages[:count]
+	f.messages = f.messages[count:]
+
+	return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+}
+
+func (f *fakeDLQ) SendMessageWithContext(ctx aws.Context, input *sqs.SendMessageInput, opts ...request.Option) (*sqs.SendMessageOutput, error) {
+	f.sent[*input.QueueUrl] = append(f.sent[*input.QueueUrl], *input.MessageBody)
+
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (f *fakeDLQ) DeleteMessageWithContext(ctx aws.Context, input *sqs.DeleteMessageInput, opts ...request.Option) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, *input.ReceiptHandle)
+
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeDLQ) ChangeMessageVisibilityBatch(input *sqs.ChangeMessageVisibilityBatchInput) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	for _, entry := range input.Entries {
+		f.released = append(f.released, *entry.ReceiptHandle)
+	}
+
+	return &sqs.ChangeMessageVisibilityBatchOutput{}, nil
+}
+
+type deadLetterTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	sqs    *fakeDLQ
+	dlq    *SQSDeadLetterQueue
+}
+
+func TestDeadLetterTestSuite(t *testing.T) {
+	suite.Run(t, new(deadLetterTestSuite))
+}
+
+func (s *deadLetterTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *deadLetterTestSuite) SetupTest() {
+	s.sqs = &fakeDLQ{sent: map[string][]string{}}
+	s.dlq = NewSQSDeadLetterQueue(s.sqs, nil, "subscriber", "topic")
+
+	for _, id := range []string{"a", "b", "c"} {
+		mess := newSNSMessage(id, `{\"value\":\"`+id+`\"}`)
+		mess.Attributes = map[string]*string{"ApproximateReceiveCount": aws.String("5")}
+		s.sqs.messages = append(s.sqs.messages, mess)
+	}
+}
+
+func (s *deadLetterTestSuite) TestListReleasesMessages() {
+	messages, err := s.dlq.List(context.Background(), 2)
+
+	s.assert.NoError(err)
+	s.assert.Len(messages, 2)
+	s.assert.Equal("a", messages[0].ID)
+	s.assert.Equal(5, messages[0].ReceiveCount)
+	s.assert.Equal(`{"value":"a"}`, string(messages[0].Body))
+
+	s.assert.Equal([]string{"a-receipt", "b-receipt"}, s.sqs.released)
+	s.assert.Empty(s.sqs.deleted)
+}
+
+func (s *deadLetterTestSuite) TestReplaySelectedToQueue() {
+	replayed, err := s.dlq.Replay(context.Background(), &ReplayRequest{
+		MessageIDs: []string{"b"},
+		Target:     ReplayToQueue,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(1, replayed)
+	s.assert.Len(s.sqs.sent["subscriber"], 1)
+	s.assert.Equal([]string{"b-receipt"}, s.sqs.deleted)
+	s.assert.Equal([]string{"a-receipt", "c-receipt"}, s.sqs.released)
+}
+
+func (s *deadLetterTestSuite) TestReplayToTopicWithoutPublisher() {
+	_, err := s.dlq.Replay(context.Background(), &ReplayRequest{Target: ReplayToTopic})
+
+	s.assert.Error(err)
+	s.assert.Empty(s.sqs.deleted)
+}
+
+func (s *deadLetterTestSuite) TestDeadLetterQueueName() {
+	s.assert.Equal("subscriber_dlq", DeadLetterQueueName("subscriber"))
+	s.assert.Equal("subscriber_dlq.fifo", DeadLetterQueueName("subscriber.fifo"))
+}
@@ -8,7 +8,15 @@ import (
 
 // Error used as a wrapper for all application errors
 type Error struct {
-	Key string
+	Key        string
+	Violations []*Violation
+}
+
+// Violation describes a single field that failed validation
+type Violation struct {
+	Path    string
+	Rule    string
+	Message string
 }
 
 func newError(key string) *Error {
@@ -19,6 +27,17 @@ func (e *Error) Error() string {
 	return e.Key
 }
 
+// Is reports whether target is an application error with the same key,
+// so errors carrying violations still match their sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Key == t.Key
+}
+
 var (
 	// ErrValidationFailed returned when an entity has a invalid field
 	ErrValidationFailed = newError("validation-failed")
@@ -29,6 +48,9 @@ var (
 	// ErrNotFound returned when an entity is not found
 	ErrNotFound = newError("not-found")
 
+	// ErrForbidden returned when the user is not allowed to change an entity
+	ErrForbidden = newError("forbidden")
+
 	// ErrInvalidCredentials returned when the password or email is invalid
 	ErrInvalidCredentials = newError("invalid-credentials")
 
@@ -45,11 +67,67 @@ var (
 
 	// ErrItemsTradeFailed returned when an error occured when trying to trade the items
 	ErrItemsTradeFailed = newError("items-trade-failed")
+
+	// ErrTradeSelfOffer returned when the owner and the wanted items owner are the same user
+	ErrTradeSelfOffer = newError("trade-self-offer")
+
+	// ErrTradeDuplicatedItems returned when the same item appears twice in one side of a trade
+	ErrTradeDuplicatedItems = newError("trade-duplicated-items")
+
+	// ErrTradeItemOfferedAndWanted returned when an item is both offered and wanted
+	ErrTradeItemOfferedAndWanted = newError("trade-item-offered-and-wanted")
+
+	// ErrTradeTooManyItems returned when a side of a trade exceeds the max items allowed
+	ErrTradeTooManyItems = newError("trade-too-many-items")
+
+	// ErrTradeQuantityLimitExceeded returned when an item quantity exceeds the max allowed
+	ErrTradeQuantityLimitExceeded = newError("trade-quantity-limit-exceeded")
+
+	// ErrTradeCurrencyLimitExceeded returned when the currency of an offer exceeds the max allowed
+	ErrTradeCurrencyLimitExceeded = newError("trade-currency-limit-exceeded")
+
+	// ErrTradeOpenOffersLimitExceeded returned when the owner reached the max open offers
+	ErrTradeOpenOffersLimitExceeded = newError("trade-open-offers-limit-exceeded")
+
+	// ErrTradePairOffersLimitExceeded returned when two users reached the max open offers between them
+	ErrTradePairOffersLimitExceeded = newError("trade-pair-offers-limit-exceeded")
+
+	// ErrTradeAlreadyAccepted returned when a participant accepts the same trade twice
+	ErrTradeAlreadyAccepted = newError("trade-already-accepted")
+
+	// ErrTradeAlreadyClaimed returned when another user claimed the listing first
+	ErrTradeAlreadyClaimed = newError("trade-already-claimed")
+
+	// ErrTradeChanged returned when the offer differs from the revision the user expected
+	ErrTradeChanged = newError("trade-changed")
+
+	// ErrTradeBlocked returned when the wanted items owner blocked the sender
+	ErrTradeBlocked = newError("trade-blocked")
+
+	// ErrReputationTooLow returned when the owner score is below the minimum to create offers
+	ErrReputationTooLow = newError("reputation-too-low")
+
+	// ErrFeedbackAlreadySubmitted returned when a participant rates the same trade twice
+	ErrFeedbackAlreadySubmitted = newError("feedback-already-submitted")
+
+	// ErrAuctionClosed returned when bidding or accepting a bid after the auction was closed
+	ErrAuctionClosed = newError("auction-closed")
+
+	// ErrAuctionSelfBid returned when the owner bids on their own auction
+	ErrAuctionSelfBid = newError("auction-self-bid")
 )
 
 // RestError used as a Rest api call error
 type RestError struct {
-	Key string `json:"key"`
+	Key        string           `json:"key"`
+	Violations []*RestViolation `json:"violations,omitempty"`
+}
+
+// RestViolation used as a Rest api field validation error
+type RestViolation struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 // ErrorStatusMap mapping between application erros and status codes
@@ -60,6 +138,36 @@ var ErrorStatusMap = map[string]int{
 	ErrLockFailed.Key:         http.StatusBadRequest,
 	ErrItemsTradeFailed.Key:   http.StatusBadRequest,
 	ErrNotFound.Key:           http.StatusNotFound,
+	ErrForbidden.Key:          http.StatusForbidden,
+
+	ErrTradeSelfOffer.Key:               http.StatusUnprocessableEntity,
+	ErrTradeDuplicatedItems.Key:         http.StatusUnprocessableEntity,
+	ErrTradeItemOfferedAndWanted.Key:    http.StatusUnprocessableEntity,
+	ErrTradeTooManyItems.Key:            http.StatusUnprocessableEntity,
+	ErrTradeQuantityLimitExceeded.Key:   http.StatusUnprocessableEntity,
+	ErrTradeCurrencyLimitExceeded.Key:   http.StatusUnprocessableEntity,
+	ErrTradeOpenOffersLimitExceeded.Key: http.StatusConflict,
+	ErrTradePairOffersLimitExceeded.Key: http.StatusConflict,
+	ErrTradeInvalidStatus.Key:           http.StatusConflict,
+	ErrTradeAlreadyAccepted.Key:         http.StatusConflict,
+	ErrTradeAlreadyClaimed.Key:          http.StatusConflict,
+	ErrTradeChanged.Key:                 http.StatusConflict,
+	ErrTradeBlocked.Key:                 http.StatusForbidden,
+	ErrReputationTooLow.Key:             http.StatusForbidden,
+	ErrFeedbackAlreadySubmitted.Key:     http.StatusConflict,
+	ErrAuctionClosed.Key:                http.StatusConflict,
+	ErrAuctionSelfBid.Key:               http.StatusUnprocessableEntity,
+}
+
+// ErrorKey returns the key an error is reported with by the rest api
+func ErrorKey(err error) string {
+	if ierr, ok := err.(*Error); ok {
+		if _, exists := ErrorStatusMap[ierr.Key]; exists {
+			return ierr.Key
+		}
+	}
+
+	return "internal-server-error"
 }
 
 // HandleRestError handles applications errors using ErrorStatusMap
@@ -67,7 +175,10 @@ func HandleRestError(ctx *gin.Context, err error) {
 
 	if ierr, ok := err.(*Error); ok {
 		if s, exists := ErrorStatusMap[ierr.Key]; exists {
-			ctx.JSON(s, &RestError{Key: ierr.Key})
+			ctx.JSON(s, &RestError{
+				Key:        ierr.Key,
+				Violations: parseViolations(ierr.Violations),
+			})
 			return
 		}
 	}
@@ -75,3 +186,21 @@ func HandleRestError(ctx *gin.Context, err error) {
 	ctx.JSON(http.StatusInternalServerError, &RestError{Key: "internal-server-error"})
 	return
 }
+
+func parseViolations(violations []*Violation) []*RestViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	result := make([]*RestViolation, len(violations))
+
+	for i, v := range violations {
+		result[i] = &RestViolation{
+			Path:    v.Path,
+			Rule:    v.Rule,
+			Message: v.Message,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package core
+
+import "github.com/google/uuid"
+
+// Event a payload to publish, GroupID orders the events of the same entity on
+// FIFO topics and is ignored by standard ones. ID becomes the envelope id, it
+// is generated when empty and kept on the event so publishing it again reuses it
+type Event struct {
+	ID            string
+	Type          string
+	CorrelationID string
+	GroupID       string
+	Payload       interface{}
+}
+
+// EventPublisher wraps every event in a validated envelope before publishing it
+type EventPublisher struct {
+	publisher Publisher
+	registry  *EventRegistry
+	producer  string
+}
+
+// NewEventPublisher producer is the name of the service publishing the events
+func NewEventPublisher(publisher Publisher, registry *EventRegistry, producer string) *EventPublisher {
+	return &EventPublisher{
+		publisher: publisher,
+		registry:  registry,
+		producer:  producer,
+	}
+}
+
+// Publish events failing validation are never published, the event type and
+// correlation id are also sent as attributes so subscriptions can filter on
+// them. On FIFO topics events of a group are delivered in order and
+// publishing the same event again, or another one with its id, is
+// deduplicated
+func (p *EventPublisher) Publish(topicID string, event *Event) (string, error) {
+	envelope, err := p.registry.NewEnvelope(event.Type, event.CorrelationID, p.producer, event.Payload)
+	if err != nil {
+		return "", err
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+
+	envelope.ID = event.ID
+
+	return p.publisher.PublishWithOptions(topicID, envelope, &PublishOptions{
+		Attributes: map[string]string{
+			EventTypeAttribute:     envelope.Type,
+			CorrelationIDAttribute: envelope.CorrelationID,
+		},
+		GroupID:         event.GroupID,
+		DeduplicationID: envelope.ID,
+	})
+}
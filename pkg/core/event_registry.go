@@ -0,0 +1,252 @@
+package core
+
+import (
+	"bytes"
+	_ "embed" // envelope schema
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/envelope.json
+var envelopeSchema []byte
+
+// ErrInvalidEvent returned when an envelope or its payload does not match its
+// schema, it wraps ErrMalformedMessage so consumers do not wait to retry it
+var ErrInvalidEvent = fmt.Errorf("%w: invalid event", ErrMalformedMessage)
+
+var schemaPath = regexp.MustCompile(`^([a-z0-9.-]+)/v([0-9]+)\.json$`)
+
+// Envelope wraps every published event with the metadata needed to validate and upcast it
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Producer      string          `json:"producer"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Upcaster converts the payload of one version of an event to the next one
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+type eventVersion struct {
+	eventType string
+	version   int
+}
+
+// EventRegistry holds the JSON Schema of every version of every event and the
+// upcasters between consecutive versions
+type EventRegistry struct {
+	envelope  *jsonschema.Schema
+	schemas   map[eventVersion]*jsonschema.Schema
+	latest    map[string]int
+	upcasters map[eventVersion]Upcaster
+}
+
+// NewEventRegistry loads the schemas stored as <type>/v<version>.json
+func NewEventRegistry(schemas ...fs.FS) (*EventRegistry, error) {
+	registry := &EventRegistry{
+		schemas:   map[eventVersion]*jsonschema.Schema{},
+		latest:    map[string]int{},
+		upcasters: map[eventVersion]Upcaster{},
+	}
+
+	envelope, err := compileSchema("envelope.json", envelopeSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	registry.envelope = envelope
+
+	for _, fsys := range schemas {
+		err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || path.Ext(name) != ".json" {
+				return err
+			}
+
+			match := schemaPath.FindStringSubmatch(name)
+			if match == nil {
+				return fmt.Errorf("schema %s is not named <type>/v<version>.json", name)
+			}
+
+			version, _ := strconv.Atoi(match[2])
+
+			content, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return err
+			}
+
+			schema, err := compileSchema(name, content)
+			if err != nil {
+				return err
+			}
+
+			registry.schemas[eventVersion{match[1], version}] = schema
+
+			if version > registry.latest[match[1]] {
+				registry.latest[match[1]] = version
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// RegisterUpcaster sets the upcaster from version to version+1 of the event
+func (r *EventRegistry) RegisterUpcaster(eventType string, version int, upcaster Upcaster) error {
+	if _, exists := r.schemas[eventVersion{eventType, version + 1}]; !exists {
+		return fmt.Errorf("event %s has no version %d to upcast to", eventType, version+1)
+	}
+
+	r.upcasters[eventVersion{eventType, version}] = upcaster
+
+	return nil
+}
+
+// Latest returns the version events of the type are published with
+func (r *EventRegistry) Latest(eventType string) (int, bool) {
+	version, exists := r.latest[eventType]
+	return version, exists
+}
+
+// NewEnvelope wraps the payload with the latest version of the event
+func (r *EventRegistry) NewEnvelope(eventType, correlationID, producer string, payload interface{}) (*Envelope, error) {
+	version, exists := r.Latest(eventType)
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown event type %s", ErrInvalidEvent, eventType)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &Envelope{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		Version:       version,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Producer:      producer,
+		Payload:       body,
+	}
+
+	if err := r.Validate(envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+// Validate checks the envelope and its payload against the schema of its version
+func (r *EventRegistry) Validate(envelope *Envelope) error {
+	schema, exists := r.schemas[eventVersion{envelope.Type, envelope.Version}]
+	if !exists {
+		return fmt.Errorf("%w: unknown event %s version %d", ErrInvalidEvent, envelope.Type, envelope.Version)
+	}
+
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	if err := validateJSON(r.envelope, content); err != nil {
+		return fmt.Errorf("%w: envelope: %v", ErrInvalidEvent, err)
+	}
+
+	if err := validateJSON(schema, envelope.Payload); err != nil {
+		return fmt.Errorf("%w: %s version %d: %v", ErrInvalidEvent, envelope.Type, envelope.Version, err)
+	}
+
+	return nil
+}
+
+// Upcast validates the envelope and converts its payload to the latest version
+func (r *EventRegistry) Upcast(envelope *Envelope) error {
+	if err := r.Validate(envelope); err != nil {
+		return err
+	}
+
+	latest := r.latest[envelope.Type]
+
+	if envelope.Version == latest {
+		return nil
+	}
+
+	for envelope.Version < latest {
+		upcaster, exists := r.upcasters[eventVersion{envelope.Type, envelope.Version}]
+		if !exists {
+			return fmt.Errorf("%w: no upcaster for %s version %d", ErrInvalidEvent, envelope.Type, envelope.Version)
+		}
+
+		payload, err := upcaster(envelope.Payload)
+		if err != nil {
+			return fmt.Errorf("%w: upcasting %s version %d: %v", ErrInvalidEvent, envelope.Type, envelope.Version, err)
+		}
+
+		envelope.Payload = payload
+		envelope.Version++
+	}
+
+	return r.Validate(envelope)
+}
+
+// DecodeEnvelope unmarshals, validates and upcasts an enveloped message body
+func (r *EventRegistry) DecodeEnvelope(body []byte) (*Envelope, error) {
+	envelope := new(Envelope)
+
+	if err := json.Unmarshal(body, envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	if err := r.Upcast(envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+func compileSchema(name string, content []byte) (*jsonschema.Schema, error) {
+	url := "file:///schemas/" + name
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+
+	if err := compiler.AddResource(url, bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("error loading schema %s: %w", name, err)
+	}
+
+	schema, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling schema %s: %w", name, err)
+	}
+
+	return schema, nil
+}
+
+func validateJSON(schema *jsonschema.Schema, content []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	return schema.Validate(value)
+}
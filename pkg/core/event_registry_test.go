@@ -0,0 +1,167 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var testSchemas = fstest.MapFS{
+	"user-renamed/v1.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"name": {"type": "string", "minLength": 1}}
+	}`)},
+	"user-renamed/v2.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["first_name", "last_name"],
+		"properties": {
+			"first_name": {"type": "string", "minLength": 1},
+			"last_name": {"type": "string"}
+		}
+	}`)},
+}
+
+type userRenamedV2 struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type eventRegistryTestSuite struct {
+	suite.Suite
+	assert   *assert.Assertions
+	registry *core.EventRegistry
+}
+
+func TestEventRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(eventRegistryTestSuite))
+}
+
+func (s *eventRegistryTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *eventRegistryTestSuite) SetupTest() {
+	registry, err := core.NewEventRegistry(testSchemas)
+	s.Require().NoError(err)
+
+	err = registry.RegisterUpcaster("user-renamed", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		v1 := struct {
+			Name string `json:"name"`
+		}{}
+
+		if err := json.Unmarshal(payload, &v1); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(&userRenamedV2{FirstName: v1.Name})
+	})
+	s.Require().NoError(err)
+
+	s.registry = registry
+}
+
+func (s *eventRegistryTestSuite) TestPublishUsesLatestVersion() {
+	broker := core.NewInMemoryBroker(nil)
+	received := make(chan *core.Message, 1)
+
+	subscriber := broker.Subscriber(&core.Subscription{
+		SubscriberID: "users",
+		TopicID:      "user-renamed",
+		Middlewares:  []core.Middleware{core.HandlerEnvelope(s.registry)},
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			received <- message
+			return nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+
+	publisher := core.NewEventPublisher(broker, s.registry, "tests")
+
+	_, err := publisher.Publish("user-renamed", &core.Event{
+		Type:          "user-renamed",
+		CorrelationID: "abc",
+		Payload:       &userRenamedV2{FirstName: "Ada"},
+	})
+	s.assert.NoError(err)
+
+	message := <-received
+
+	s.assert.Equal(2, message.Envelope.Version)
+	s.assert.Equal("tests", message.Envelope.Producer)
+	s.assert.Equal("abc", message.CorrelationID)
+	s.assert.JSONEq(`{"first_name":"Ada","last_name":""}`, string(message.Body))
+}
+
+func (s *eventRegistryTestSuite) TestPublishInvalidPayload() {
+	publisher := core.NewEventPublisher(core.NewInMemoryBroker(nil), s.registry, "tests")
+
+	_, err := publisher.Publish("user-renamed", &core.Event{Type: "user-renamed", Payload: &userRenamedV2{}})
+
+	s.assert.ErrorIs(err, core.ErrInvalidEvent)
+}
+
+func (s *eventRegistryTestSuite) TestPublishAgainReusesEventID() {
+	publisher := core.NewEventPublisher(core.NewInMemoryBroker(nil), s.registry, "tests")
+
+	event := &core.Event{
+		Type:    "user-renamed",
+		GroupID: "user",
+		Payload: &userRenamedV2{FirstName: "Ada"},
+	}
+
+	first, err := publisher.Publish("user-renamed.fifo", event)
+	s.assert.NoError(err)
+	s.assert.NotEmpty(event.ID)
+
+	second, err := publisher.Publish("user-renamed.fifo", event)
+	s.assert.NoError(err)
+	s.assert.Equal(first, second)
+}
+
+func (s *eventRegistryTestSuite) TestUpcastOlderVersion() {
+	envelope, err := s.registry.DecodeEnvelope(s.encode(&core.Envelope{
+		ID:         "1",
+		Type:       "user-renamed",
+		Version:    1,
+		OccurredAt: time.Now(),
+		Producer:   "tests",
+		Payload:    json.RawMessage(`{"name":"Ada"}`),
+	}))
+
+	s.assert.NoError(err)
+	s.assert.Equal(2, envelope.Version)
+	s.assert.JSONEq(`{"first_name":"Ada","last_name":""}`, string(envelope.Payload))
+}
+
+func (s *eventRegistryTestSuite) TestDecodeInvalidEnvelope() {
+	cases := map[string]*core.Envelope{
+		"unknown type": {ID: "1", Type: "user-deleted", Version: 1, OccurredAt: time.Now(), Producer: "tests", Payload: json.RawMessage(`{}`)},
+		"no producer":  {ID: "1", Type: "user-renamed", Version: 2, OccurredAt: time.Now(), Payload: json.RawMessage(`{"first_name":"Ada","last_name":""}`)},
+		"bad payload":  {ID: "1", Type: "user-renamed", Version: 1, OccurredAt: time.Now(), Producer: "tests", Payload: json.RawMessage(`{"name":""}`)},
+	}
+
+	for name, envelope := range cases {
+		_, err := s.registry.DecodeEnvelope(s.encode(envelope))
+
+		s.assert.True(errors.Is(err, core.ErrMalformedMessage), name)
+	}
+}
+
+func (s *eventRegistryTestSuite) encode(envelope *core.Envelope) []byte {
+	body, err := json.Marshal(envelope)
+	s.Require().NoError(err)
+
+	return body
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"encoding/json"
+)
+
+const (
+	// EventTypeAttribute message attribute carrying the type of the enveloped event
+	EventTypeAttribute = "event_type"
+)
+
+// FilterPolicy restricts a subscription to the messages whose attributes match,
+// every attribute of the policy must be set to one of its values. It is the
+// exact string matching subset of SNS filter policies
+type FilterPolicy map[string][]string
+
+// Matches reports whether a message with the attributes is delivered
+func (p FilterPolicy) Matches(attributes map[string]string) bool {
+	for name, values := range p {
+		value, exists := attributes[name]
+		if !exists || !contains(values, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// JSON returns the policy in the format of the SNS FilterPolicy subscription attribute
+func (p FilterPolicy) JSON() (string, error) {
+	content, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,24 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterPolicyMatches(t *testing.T) {
+	policy := core.FilterPolicy{
+		"event_type": {"TradeAccepted", "TradeCompleted"},
+		"producer":   {"tradew-trades"},
+	}
+
+	assert.True(t, policy.Matches(map[string]string{"event_type": "TradeCompleted", "producer": "tradew-trades", "other": "x"}))
+	assert.False(t, policy.Matches(map[string]string{"event_type": "TradeCompleted"}))
+	assert.False(t, policy.Matches(map[string]string{"event_type": "TradeDeclined", "producer": "tradew-trades"}))
+	assert.True(t, core.FilterPolicy(nil).Matches(nil))
+
+	content, err := policy.JSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"event_type":["TradeAccepted","TradeCompleted"],"producer":["tradew-trades"]}`, content)
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type correlationIDKey struct{}
+
+// MetricsRecorder receives the outcome of every handled message
+type MetricsRecorder interface {
+	ObserveMessage(subscriberID string, duration time.Duration, err error)
+}
+
+// CorrelationIDFromContext returns the correlation id set by HandlerCorrelationID
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// HandlerRecovery turns a handler panic into an error so the message is retried
+func HandlerRecovery() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logrus.
+						WithFields(logrus.Fields{
+							"err":        r,
+							"stack":      string(debug.Stack()),
+							"message_id": message.ID,
+						}).
+						Error("handler panic")
+
+					err = fmt.Errorf("handler panic: %v", r)
+				}
+			}()
+
+			return next.Handle(ctx, message)
+		})
+	}
+}
+
+// HandlerCorrelationID traces the message through the handler by putting its
+// correlation id in the context, a new one is created when it has none
+func HandlerCorrelationID() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) error {
+			if message.CorrelationID == "" {
+				message.CorrelationID = uuid.NewString()
+			}
+
+			return next.Handle(context.WithValue(ctx, correlationIDKey{}, message.CorrelationID), message)
+		})
+	}
+}
+
+// HandlerEnvelope validates and upcasts the event envelope, the handler then
+// receives the payload of the latest version as the message body
+func HandlerEnvelope(registry *EventRegistry) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) error {
+			envelope, err := registry.DecodeEnvelope(message.Body)
+			if err != nil {
+				return err
+			}
+
+			message.Envelope = envelope
+			message.Body = envelope.Payload
+
+			if message.CorrelationID == "" {
+				message.CorrelationID = envelope.CorrelationID
+			}
+
+			return next.Handle(ctx, message)
+		})
+	}
+}
+
+// HandlerInbox skips the messages the subscriber already processed, it must
+// come after HandlerEnvelope so events are identified by their envelope id
+func HandlerInbox(subscriberID string, inbox *Inbox) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) error {
+			return inbox.Process(ctx, subscriberID, InboxMessageID(message), func(ctx context.Context) error {
+				return next.Handle(ctx, message)
+			})
+		})
+	}
+}
+
+// HandlerLog logs every handled message
+func HandlerLog(subscriberID string) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) error {
+			start := time.Now()
+
+			err := next.Handle(ctx, message)
+
+			entry := logrus.WithFields(logrus.Fields{
+				"subscriber_id":  subscriberID,
+				"message_id":     message.ID,
+				"receive_count":  message.ReceiveCount,
+				"correlation_id": message.CorrelationID,
+				"latency":        time.Since(start),
+			})
+
+			if err != nil {
+				entry.WithError(err).Error("error handling message")
+			} else {
+				entry.Info("message handled")
+			}
+
+			return err
+		})
+	}
+}
+
+// HandlerMetrics reports the duration and result of every handled message
+func HandlerMetrics(subscriberID string, recorder MetricsRecorder) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, message *Message) error {
+			start := time.Now()
+
+			err := next.Handle(ctx, message)
+
+			recorder.ObserveMessage(subscriberID, time.Since(start), err)
+
+			return err
+		})
+	}
+}
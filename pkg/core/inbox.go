@@ -0,0 +1,239 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	inboxProcessing = "processing"
+	inboxProcessed  = "processed"
+
+	// defaultInboxTTL sqs keeps a message for at most 14 days so no redelivery
+	// can arrive after its record expired
+	defaultInboxTTL   = 14 * 24 * time.Hour
+	defaultInboxLease = 5 * time.Minute
+)
+
+// ErrMessageInProgress returned when another consumer is handling the same
+// message, it is retried and skipped once that consumer finishes
+var ErrMessageInProgress = errors.New("message in progress")
+
+// errInboxDuplicate aborts the transaction of a message already processed
+var errInboxDuplicate = errors.New("message already processed")
+
+// Inbox records the messages a subscriber processed so redelivered ones are
+// skipped. Records expire after the ttl and, unless transactional, a message
+// is claimed for the lease while handled so a crashed consumer does not keep
+// it forever
+type Inbox struct {
+	client        *mongo.Client
+	collection    *mongo.Collection
+	ttl           time.Duration
+	lease         time.Duration
+	transactional bool
+}
+
+// InboxOption ...
+type InboxOption func(*Inbox)
+
+type inboxRecord struct {
+	ID          string     `bson:"_id"`
+	Status      string     `bson:"status"`
+	LockedUntil *time.Time `bson:"locked_until,omitempty"`
+	ProcessedAt *time.Time `bson:"processed_at,omitempty"`
+	ExpiresAt   time.Time  `bson:"expires_at"`
+}
+
+// WithInboxTTL - default 14 days
+func WithInboxTTL(ttl time.Duration) InboxOption {
+	return func(i *Inbox) {
+		if ttl > 0 {
+			i.ttl = ttl
+		}
+	}
+}
+
+// WithInboxLease - default 5 minutes, it should be longer than the handler
+// takes or a redelivery may run while the first one is still handled
+func WithInboxLease(lease time.Duration) InboxOption {
+	return func(i *Inbox) {
+		if lease > 0 {
+			i.lease = lease
+		}
+	}
+}
+
+// WithInboxTransaction runs the handler and the inbox record in a single
+// transaction, it requires a replica set and the handler to do its writes
+// with the context it receives. The driver runs the handler again on every
+// transient transaction error, so calls it makes outside mongodb, such as the
+// inventory unlocks of a closed offer, must be safe to repeat
+func WithInboxTransaction() InboxOption {
+	return func(i *Inbox) {
+		i.transactional = true
+	}
+}
+
+// NewInbox ...
+func NewInbox(client *mongo.Client, database string, opts ...InboxOption) *Inbox {
+	inbox := &Inbox{
+		client:     client,
+		collection: client.Database(database).Collection("inbox"),
+		ttl:        defaultInboxTTL,
+		lease:      defaultInboxLease,
+	}
+
+	for _, opt := range opts {
+		opt(inbox)
+	}
+
+	inbox.createIndex()
+
+	return inbox
+}
+
+// Process runs fn once per subscriber and message id, it returns nil without
+// running fn for messages already processed
+func (i *Inbox) Process(ctx context.Context, subscriberID, messageID string, fn func(ctx context.Context) error) error {
+	id := inboxID(subscriberID, messageID)
+
+	if i.transactional {
+		return i.processInTransaction(ctx, id, fn)
+	}
+
+	claimed, err := i.claim(ctx, id)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := fn(ctx); err != nil {
+		// released so the redelivery can claim it again
+		if _, releaseErr := i.collection.DeleteOne(ctx, bson.M{"_id": id, "status": inboxProcessing}); releaseErr != nil {
+			logrus.WithError(releaseErr).
+				Errorf("error releasing inbox message %s", id)
+		}
+
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	_, err = i.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": inboxProcessed, "processed_at": now, "expires_at": now.Add(i.ttl)},
+		"$unset": bson.M{"locked_until": ""},
+	})
+
+	return err
+}
+
+// claim the upsert only matches a claim whose lease expired, when the record
+// is processed or claimed by another consumer the insert fails on the _id
+func (i *Inbox) claim(ctx context.Context, id string) (bool, error) {
+	now := time.Now().UTC()
+
+	_, err := i.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": inboxProcessing, "locked_until": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{"locked_until": now.Add(i.lease), "expires_at": now.Add(i.ttl)}},
+		options.Update().SetUpsert(true),
+	)
+
+	if err == nil {
+		return true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	record := new(inboxRecord)
+
+	err = i.collection.FindOne(ctx, bson.M{"_id": id}).Decode(record)
+
+	// released by a failed consumer in the meantime
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, ErrMessageInProgress
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if record.Status == inboxProcessed {
+		return false, nil
+	}
+
+	return false, ErrMessageInProgress
+}
+
+// processInTransaction the record is inserted first so a concurrent delivery
+// conflicts with it, the transaction is retried and then finds it processed
+func (i *Inbox) processInTransaction(ctx context.Context, id string, fn func(ctx context.Context) error) error {
+	session, err := i.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		now := time.Now().UTC()
+
+		_, err := i.collection.InsertOne(sc, &inboxRecord{
+			ID:          id,
+			Status:      inboxProcessed,
+			ProcessedAt: &now,
+			ExpiresAt:   now.Add(i.ttl),
+		})
+
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errInboxDuplicate
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fn(sc)
+	})
+
+	if errors.Is(err, errInboxDuplicate) {
+		return nil
+	}
+
+	return err
+}
+
+func (i *Inbox) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	i.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+}
+
+// InboxMessageID the envelope id is kept when an event is republished or
+// replayed to the topic, other messages are identified by the broker id
+func InboxMessageID(message *Message) string {
+	if message.Envelope != nil && message.Envelope.ID != "" {
+		return message.Envelope.ID
+	}
+
+	return message.ID
+}
+
+// inboxID one collection is shared by every subscriber, each of them has to
+// process the same message
+func inboxID(subscriberID, messageID string) string {
+	return subscriberID + "/" + messageID
+}
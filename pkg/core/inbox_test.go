@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type inboxTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	ctx    context.Context
+}
+
+func TestInboxTestSuite(t *testing.T) {
+	suite.Run(t, new(inboxTestSuite))
+}
+
+func (s *inboxTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *inboxTestSuite) TestInboxMessageIDPrefersEnvelope() {
+	message := &Message{ID: "sqs-id"}
+	s.assert.Equal("sqs-id", InboxMessageID(message))
+
+	message.Envelope = &Envelope{ID: "event-id"}
+	s.assert.Equal("event-id", InboxMessageID(message))
+}
+
+func (s *inboxTestSuite) TestInboxIDIsPerSubscriber() {
+	s.assert.NotEqual(inboxID("first", "event-id"), inboxID("second", "event-id"))
+}
+
+func (s *inboxTestSuite) TestOptions() {
+	inbox := &Inbox{ttl: defaultInboxTTL, lease: defaultInboxLease}
+
+	WithInboxTTL(0)(inbox)
+	WithInboxLease(0)(inbox)
+	s.assert.Equal(defaultInboxTTL, inbox.ttl)
+	s.assert.Equal(defaultInboxLease, inbox.lease)
+	s.assert.False(inbox.transactional)
+
+	WithInboxTransaction()(inbox)
+	s.assert.True(inbox.transactional)
+}
+
+func (s *inboxTestSuite) TestProcessSkipsProcessedMessage() {
+	inbox, _ := s.newInbox()
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		err := inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+
+		s.assert.NoError(err)
+	}
+
+	s.assert.Equal(1, calls)
+
+	// other subscribers still process it
+	err := inbox.Process(s.ctx, "other", "message", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(2, calls)
+}
+
+func (s *inboxTestSuite) TestProcessConcurrentDeliveriesClaimOnce() {
+	inbox, _ := s.newInbox()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	calls := 0
+	errs := []error{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			})
+
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	s.assert.Equal(1, calls)
+
+	for _, err := range errs {
+		if err != nil {
+			s.assert.ErrorIs(err, ErrMessageInProgress)
+		}
+	}
+}
+
+func (s *inboxTestSuite) TestProcessInProgress() {
+	inbox, _ := s.newInbox()
+
+	claimed, err := inbox.claim(s.ctx, inboxID("subscriber", "message"))
+	s.assert.NoError(err)
+	s.assert.True(claimed)
+
+	err = inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		s.Fail("message claimed by another consumer was processed")
+		return nil
+	})
+
+	s.assert.ErrorIs(err, ErrMessageInProgress)
+}
+
+func (s *inboxTestSuite) TestProcessTakesOverExpiredLease() {
+	inbox, _ := s.newInbox(WithInboxLease(100 * time.Millisecond))
+
+	// a consumer that crashed while handling it
+	claimed, err := inbox.claim(s.ctx, inboxID("subscriber", "message"))
+	s.assert.NoError(err)
+	s.assert.True(claimed)
+
+	time.Sleep(150 * time.Millisecond)
+
+	calls := 0
+
+	err = inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(1, calls)
+
+	record := s.record(inbox, "subscriber", "message")
+	s.assert.Equal(inboxProcessed, record.Status)
+	s.assert.Nil(record.LockedUntil)
+}
+
+func (s *inboxTestSuite) TestProcessReleasesFailedMessage() {
+	inbox, _ := s.newInbox()
+	handlerErr := errors.New("handler failed")
+
+	err := inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		return handlerErr
+	})
+
+	s.assert.ErrorIs(err, handlerErr)
+
+	count, err := inbox.collection.CountDocuments(s.ctx, bson.M{"_id": inboxID("subscriber", "message")})
+	s.assert.NoError(err)
+	s.assert.Equal(int64(0), count)
+
+	calls := 0
+
+	err = inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(1, calls)
+}
+
+func (s *inboxTestSuite) TestProcessInTransaction() {
+	inbox, database := s.newInbox(WithInboxTransaction())
+	s.requireReplicaSet(inbox.client)
+
+	writes := inbox.client.Database(database).Collection("writes")
+	s.assert.NoError(writes.Database().CreateCollection(s.ctx, "writes"))
+
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		err := inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+			calls++
+
+			_, err := writes.InsertOne(ctx, bson.M{"_id": uuid.NewString()})
+			return err
+		})
+
+		s.assert.NoError(err)
+	}
+
+	s.assert.Equal(1, calls)
+
+	count, err := writes.CountDocuments(s.ctx, bson.M{})
+	s.assert.NoError(err)
+	s.assert.Equal(int64(1), count)
+
+	s.assert.Equal(inboxProcessed, s.record(inbox, "subscriber", "message").Status)
+}
+
+func (s *inboxTestSuite) TestProcessInTransactionRollsBackFailedMessage() {
+	inbox, database := s.newInbox(WithInboxTransaction())
+	s.requireReplicaSet(inbox.client)
+
+	writes := inbox.client.Database(database).Collection("writes")
+	s.assert.NoError(writes.Database().CreateCollection(s.ctx, "writes"))
+
+	handlerErr := errors.New("handler failed")
+
+	err := inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		if _, err := writes.InsertOne(ctx, bson.M{"_id": uuid.NewString()}); err != nil {
+			return err
+		}
+
+		return handlerErr
+	})
+
+	s.assert.ErrorIs(err, handlerErr)
+
+	// neither the handler write nor the record were committed
+	count, err := writes.CountDocuments(s.ctx, bson.M{})
+	s.assert.NoError(err)
+	s.assert.Equal(int64(0), count)
+
+	calls := 0
+
+	err = inbox.Process(s.ctx, "subscriber", "message", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(1, calls)
+}
+
+// newInbox connects to the server in MONGODB_TEST_URI and returns an inbox in
+// a database dropped after the test, tests that need it are skipped when it
+// is not set
+func (s *inboxTestSuite) newInbox(opts ...InboxOption) (*Inbox, string) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		s.T().Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	s.Require().NoError(err)
+	s.Require().NoError(client.Ping(ctx, nil))
+
+	database := "tradew_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	s.T().Cleanup(func() {
+		client.Database(database).Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+
+	return NewInbox(client, database, opts...), database
+}
+
+// requireReplicaSet transactions are only supported by replica set members
+func (s *inboxTestSuite) requireReplicaSet(client *mongo.Client) {
+	hello := bson.M{}
+
+	err := client.Database("admin").RunCommand(s.ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello)
+	s.Require().NoError(err)
+
+	if _, ok := hello["setName"]; !ok {
+		s.T().Skip("MONGODB_TEST_URI is not a replica set")
+	}
+}
+
+func (s *inboxTestSuite) record(inbox *Inbox, subscriberID, messageID string) *inboxRecord {
+	record := new(inboxRecord)
+
+	err := inbox.collection.FindOne(s.ctx, bson.M{"_id": inboxID(subscriberID, messageID)}).Decode(record)
+	s.Require().NoError(err)
+
+	return record
+}
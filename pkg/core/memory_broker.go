@@ -0,0 +1,494 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultQueueSize  = 1000
+	defaultRetryDelay = time.Second
+
+	// deduplicationWindow SNS FIFO topics drop messages with a deduplication
+	// id already published within five minutes
+	deduplicationWindow = 5 * time.Minute
+)
+
+// InMemoryBroker delivers messages through channels inside the process. Like
+// SNS every queue subscribed to a topic whose filter policy matches receives a
+// copy of each message and, like the SQS redrive policy, failed messages are
+// retried until MaxRetries deliveries and then moved to the dead letter queue.
+// Queues of FIFO topics hold the messages of a group while an earlier one is
+// being retried
+type InMemoryBroker struct {
+	mu            sync.RWMutex
+	topics        map[string][]*inMemoryQueue
+	queues        map[string]*inMemoryQueue
+	deduplication map[string]*inMemoryDeduplication
+	queueSize     int
+	retryDelay    time.Duration
+}
+
+// InMemoryMessage ...
+type InMemoryMessage struct {
+	ID           string
+	Body         []byte
+	Attributes   map[string]string
+	GroupID      string
+	ReceiveCount int
+}
+
+type inMemoryDeduplication struct {
+	messageID string
+	expiresAt time.Time
+}
+
+type inMemoryQueue struct {
+	id           string
+	maxRetries   int
+	filterPolicy FilterPolicy
+	fifo         bool
+	messages     chan *InMemoryMessage
+	mu           sync.Mutex
+	deadLetters  []*InMemoryMessage
+	blocking     map[string]string
+	held         map[string][]*InMemoryMessage
+}
+
+type inMemoryDeadLetterQueue struct {
+	broker       *InMemoryBroker
+	subscription *Subscription
+}
+
+// InMemorySubscriber ...
+type InMemorySubscriber struct {
+	broker       *InMemoryBroker
+	queue        *inMemoryQueue
+	subscription *Subscription
+	handler      Handler
+}
+
+// NewInMemoryBroker zero settings use a queue size of 1000 and retry after one second
+func NewInMemoryBroker(settings *BrokerConfig) *InMemoryBroker {
+	broker := &InMemoryBroker{
+		topics:        map[string][]*inMemoryQueue{},
+		queues:        map[string]*inMemoryQueue{},
+		deduplication: map[string]*inMemoryDeduplication{},
+		queueSize:     defaultQueueSize,
+		retryDelay:    defaultRetryDelay,
+	}
+
+	if settings == nil {
+		return broker
+	}
+
+	if settings.QueueSize > 0 {
+		broker.queueSize = settings.QueueSize
+	}
+
+	if settings.RetryDelay > 0 {
+		broker.retryDelay = settings.RetryDelay
+	}
+
+	return broker
+}
+
+// Publisher ...
+func (b *InMemoryBroker) Publisher() Publisher {
+	return b
+}
+
+// Subscriber creates the queue of the subscriber and subscribes it to the
+// topic, messages published before are not delivered
+func (b *InMemoryBroker) Subscriber(subscription *Subscription) Subscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	queue, exists := b.queues[subscription.SubscriberID]
+	if !exists {
+		maxRetries := subscription.MaxRetries
+		if maxRetries < 1 {
+			maxRetries = defaultMaxRetries
+		}
+
+		queue = &inMemoryQueue{
+			id:           subscription.SubscriberID,
+			maxRetries:   maxRetries,
+			filterPolicy: subscription.FilterPolicy,
+			fifo:         IsFIFO(subscription.TopicID),
+			messages:     make(chan *InMemoryMessage, b.queueSize),
+			blocking:     map[string]string{},
+			held:         map[string][]*InMemoryMessage{},
+		}
+
+		b.queues[queue.id] = queue
+		b.topics[subscription.TopicID] = append(b.topics[subscription.TopicID], queue)
+	}
+
+	return &InMemorySubscriber{
+		broker:       b,
+		queue:        queue,
+		subscription: subscription,
+		handler:      chainHandler(subscription.Handler, subscription.Middlewares),
+	}
+}
+
+// Publish ...
+func (b *InMemoryBroker) Publish(topicID string, data interface{}) (string, error) {
+	return b.PublishWithAttributes(topicID, data, nil)
+}
+
+// PublishWithAttributes queues whose filter policy does not match the attributes get no copy
+func (b *InMemoryBroker) PublishWithAttributes(topicID string, data interface{}, attributes map[string]string) (string, error) {
+	return b.PublishWithOptions(topicID, data, &PublishOptions{Attributes: attributes})
+}
+
+// PublishWithOptions FIFO topics require a group id, a deduplication id
+// already published within the window returns the id of the first message
+func (b *InMemoryBroker) PublishWithOptions(topicID string, data interface{}, opts *PublishOptions) (string, error) {
+	fifo := IsFIFO(topicID)
+
+	if fifo && opts.GroupID == "" {
+		return "", fmt.Errorf("publishing to FIFO topic %s requires a group id", topicID)
+	}
+
+	body, err := json.Marshal(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	messageID := uuid.NewString()
+
+	if fifo && opts.DeduplicationID != "" {
+		if original, duplicated := b.deduplicate(topicID, opts.DeduplicationID, messageID); duplicated {
+			return original, nil
+		}
+	}
+
+	b.mu.RLock()
+	queues := b.topics[topicID]
+	b.mu.RUnlock()
+
+	for _, queue := range queues {
+		if !queue.filterPolicy.Matches(opts.Attributes) {
+			continue
+		}
+
+		message := &InMemoryMessage{ID: messageID, Body: body, Attributes: copyAttributes(opts.Attributes)}
+		if fifo {
+			message.GroupID = opts.GroupID
+		}
+
+		queue.enqueue(message)
+	}
+
+	return messageID, nil
+}
+
+// DeadLetters returns the messages moved to the dead letter queue of a subscriber
+func (b *InMemoryBroker) DeadLetters(subscriberID string) []*InMemoryMessage {
+	b.mu.RLock()
+	queue, exists := b.queues[subscriberID]
+	b.mu.RUnlock()
+
+	if !exists {
+		return []*InMemoryMessage{}
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	result := make([]*InMemoryMessage, len(queue.deadLetters))
+	copy(result, queue.deadLetters)
+
+	return result
+}
+
+// deduplicate records the message under the deduplication id unless a
+// message that did not expire yet already has it
+func (b *InMemoryBroker) deduplicate(topicID, deduplicationID, messageID string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	for key, entry := range b.deduplication {
+		if now.After(entry.expiresAt) {
+			delete(b.deduplication, key)
+		}
+	}
+
+	key := topicID + "/" + deduplicationID
+
+	if entry, exists := b.deduplication[key]; exists {
+		return entry.messageID, true
+	}
+
+	b.deduplication[key] = &inMemoryDeduplication{messageID: messageID, expiresAt: now.Add(deduplicationWindow)}
+
+	return messageID, false
+}
+
+// DeadLetterQueue ...
+func (b *InMemoryBroker) DeadLetterQueue(subscription *Subscription) DeadLetterQueue {
+	return &inMemoryDeadLetterQueue{broker: b, subscription: subscription}
+}
+
+// Run consumes the queue until the context is cancelled
+func (s *InMemorySubscriber) Run(ctx context.Context) error {
+	logrus.Infof("starting consumer %s with topic %s", s.subscription.SubscriberID, s.subscription.TopicID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Infof("consumer %s stopped", s.subscription.SubscriberID)
+			return nil
+		case message := <-s.queue.messages:
+			s.handle(message)
+		}
+	}
+}
+
+func (s *InMemorySubscriber) handle(message *InMemoryMessage) {
+	if s.queue.hold(message) {
+		return
+	}
+
+	message.ReceiveCount++
+
+	err := s.handler.Handle(context.Background(), &Message{
+		ID:            message.ID,
+		Body:          message.Body,
+		Attributes:    copyAttributes(message.Attributes),
+		ReceiveCount:  message.ReceiveCount,
+		CorrelationID: message.Attributes[CorrelationIDAttribute],
+		GroupID:       message.GroupID,
+	})
+
+	if err == nil {
+		s.queue.release(message)
+		return
+	}
+
+	if errors.Is(err, ErrMalformedMessage) {
+		logrus.WithError(err).WithField("content", string(message.Body)).
+			Errorf("cannot unmarshal message %s - sending to dlq", message.ID)
+
+		s.queue.deadLetter(message)
+		s.queue.release(message)
+		return
+	}
+
+	if message.ReceiveCount >= s.queue.maxRetries {
+		logrus.Errorf("message %s reached max retries - sending to dlq", message.ID)
+
+		s.queue.deadLetter(message)
+		s.queue.release(message)
+		return
+	}
+
+	s.queue.block(message)
+
+	// redelivered once the delay passes, as sqs does after the visibility timeout
+	time.AfterFunc(s.broker.retryDelay, func() {
+		s.queue.enqueue(message)
+	})
+}
+
+// enqueue never blocks the publisher, messages of a full queue are dead lettered
+// and release their group when it was blocked on them
+func (q *inMemoryQueue) enqueue(message *InMemoryMessage) {
+	select {
+	case q.messages <- message:
+	default:
+		logrus.Errorf("queue %s is full - sending message %s to dlq", q.id, message.ID)
+		q.deadLetter(message)
+		q.release(message)
+	}
+}
+
+// hold keeps the message back while another message of its group is retried
+func (q *inMemoryQueue) hold(message *InMemoryMessage) bool {
+	if !q.fifo {
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	blocking, blocked := q.blocking[message.GroupID]
+	if !blocked || blocking == message.ID {
+		return false
+	}
+
+	q.held[message.GroupID] = append(q.held[message.GroupID], message)
+
+	return true
+}
+
+// block holds the next messages of the group until the message is released
+func (q *inMemoryQueue) block(message *InMemoryMessage) {
+	if !q.fifo {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.blocking[message.GroupID] = message.ID
+}
+
+// release delivers the first message held behind the message once it
+// succeeded or was dead lettered, the group stays blocked on it so the rest
+// and the ones published meanwhile keep their order
+func (q *inMemoryQueue) release(message *InMemoryMessage) {
+	if !q.fifo {
+		return
+	}
+
+	q.mu.Lock()
+
+	if q.blocking[message.GroupID] != message.ID {
+		q.mu.Unlock()
+		return
+	}
+
+	held := q.held[message.GroupID]
+	if len(held) == 0 {
+		delete(q.blocking, message.GroupID)
+		delete(q.held, message.GroupID)
+		q.mu.Unlock()
+		return
+	}
+
+	next := held[0]
+	q.blocking[message.GroupID] = next.ID
+	q.held[message.GroupID] = held[1:]
+
+	q.mu.Unlock()
+
+	q.enqueue(next)
+}
+
+func (q *inMemoryQueue) deadLetter(message *InMemoryMessage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.deadLetters = append(q.deadLetters, message)
+}
+
+// List ...
+func (q *inMemoryDeadLetterQueue) List(ctx context.Context, max int) ([]*Message, error) {
+	result := []*Message{}
+
+	for _, message := range q.broker.DeadLetters(q.subscription.SubscriberID) {
+		if len(result) == max {
+			break
+		}
+
+		result = append(result, &Message{
+			ID:            message.ID,
+			Body:          message.Body,
+			Attributes:    copyAttributes(message.Attributes),
+			ReceiveCount:  message.ReceiveCount,
+			CorrelationID: message.Attributes[CorrelationIDAttribute],
+			GroupID:       message.GroupID,
+		})
+	}
+
+	return result, nil
+}
+
+// Replay ...
+func (q *inMemoryDeadLetterQueue) Replay(ctx context.Context, req *ReplayRequest) (int, error) {
+	if req.Target != ReplayToQueue && req.Target != ReplayToTopic {
+		return 0, fmt.Errorf("unknown replay target %s", req.Target)
+	}
+
+	queue := q.queue()
+	if queue == nil {
+		return 0, nil
+	}
+
+	selected := map[string]bool{}
+	for _, id := range req.MessageIDs {
+		selected[id] = true
+	}
+
+	queue.mu.Lock()
+	replayed := []*InMemoryMessage{}
+	kept := []*InMemoryMessage{}
+
+	for _, message := range queue.deadLetters {
+		if len(selected) > 0 && !selected[message.ID] {
+			kept = append(kept, message)
+			continue
+		}
+
+		replayed = append(replayed, message)
+	}
+
+	queue.deadLetters = kept
+	queue.mu.Unlock()
+
+	for i, message := range replayed {
+		if req.Target == ReplayToTopic {
+			_, err := q.broker.PublishWithOptions(q.subscription.TopicID, json.RawMessage(message.Body), &PublishOptions{
+				Attributes: message.Attributes,
+				GroupID:    message.GroupID,
+			})
+
+			if err != nil {
+				for _, remaining := range replayed[i:] {
+					queue.deadLetter(remaining)
+				}
+
+				return i, err
+			}
+
+			continue
+		}
+
+		message.ReceiveCount = 0
+		queue.enqueue(message)
+	}
+
+	return len(replayed), nil
+}
+
+// Purge ...
+func (q *inMemoryDeadLetterQueue) Purge(ctx context.Context) error {
+	if queue := q.queue(); queue != nil {
+		queue.mu.Lock()
+		queue.deadLetters = nil
+		queue.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (q *inMemoryDeadLetterQueue) queue() *inMemoryQueue {
+	q.broker.mu.RLock()
+	defer q.broker.mu.RUnlock()
+
+	return q.broker.queues[q.subscription.SubscriberID]
+}
+
+// copyAttributes handlers may change the attributes of the message they receive
+func copyAttributes(attributes map[string]string) map[string]string {
+	result := make(map[string]string, len(attributes))
+
+	for name, value := range attributes {
+		result[name] = value
+	}
+
+	return result
+}
@@ -0,0 +1,366 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMessage struct {
+	Value string `json:"value"`
+}
+
+type memoryBrokerTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	broker *core.InMemoryBroker
+}
+
+func TestMemoryBrokerTestSuite(t *testing.T) {
+	suite.Run(t, new(memoryBrokerTestSuite))
+}
+
+func (s *memoryBrokerTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *memoryBrokerTestSuite) SetupTest() {
+	s.broker = core.NewInMemoryBroker(&core.BrokerConfig{RetryDelay: time.Millisecond})
+}
+
+func (s *memoryBrokerTestSuite) TestFanOut() {
+	var wg sync.WaitGroup
+	received := make(chan string, 2)
+
+	for _, id := range []string{"first", "second"} {
+		id := id
+		wg.Add(1)
+
+		go s.broker.Subscriber(&core.Subscription{
+			SubscriberID: id,
+			TopicID:      "topic",
+			Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+				body := new(testMessage)
+				if err := message.Decode(body); err != nil {
+					return err
+				}
+
+				received <- id + ":" + body.Value
+				wg.Done()
+				return nil
+			}),
+		}).Run(context.Background())
+	}
+
+	_, err := s.broker.Publisher().Publish("topic", &testMessage{Value: "hello"})
+	s.assert.NoError(err)
+
+	wg.Wait()
+	close(received)
+
+	values := []string{}
+	for value := range received {
+		values = append(values, value)
+	}
+
+	s.assert.ElementsMatch([]string{"first:hello", "second:hello"}, values)
+}
+
+func (s *memoryBrokerTestSuite) TestRetryThenDeadLetter() {
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+
+	go s.broker.Subscriber(&core.Subscription{
+		SubscriberID: "failing",
+		TopicID:      "topic",
+		MaxRetries:   3,
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts++
+			if attempts == 3 {
+				close(done)
+			}
+
+			return errors.New("handler failed")
+		}),
+	}).Run(context.Background())
+
+	_, err := s.broker.Publish("topic", &testMessage{Value: "hello"})
+	s.assert.NoError(err)
+
+	<-done
+
+	s.assert.Eventually(func() bool {
+		return len(s.broker.DeadLetters("failing")) == 1
+	}, time.Second, time.Millisecond)
+
+	s.assert.Equal(3, s.broker.DeadLetters("failing")[0].ReceiveCount)
+}
+
+func (s *memoryBrokerTestSuite) TestMalformedMessageIsDeadLettered() {
+	go s.broker.Subscriber(&core.Subscription{
+		SubscriberID: "typed",
+		TopicID:      "topic",
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			return message.Decode(new(testMessage))
+		}),
+	}).Run(context.Background())
+
+	_, err := s.broker.Publish("topic", "not an object")
+	s.assert.NoError(err)
+
+	s.assert.Eventually(func() bool {
+		return len(s.broker.DeadLetters("typed")) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func (s *memoryBrokerTestSuite) TestDeadLetterQueueReplay() {
+	failing := true
+	handled := make(chan string, 2)
+
+	subscription := &core.Subscription{
+		SubscriberID: "replayed",
+		TopicID:      "topic",
+		MaxRetries:   1,
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			body := new(testMessage)
+			if err := message.Decode(body); err != nil {
+				return err
+			}
+
+			if failing {
+				return errors.New("handler failed")
+			}
+
+			handled <- body.Value
+			return nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	subscriber := s.broker.Subscriber(subscription)
+
+	go func() {
+		subscriber.Run(ctx)
+		close(done)
+	}()
+
+	for _, value := range []string{"first", "second"} {
+		_, err := s.broker.Publish("topic", &testMessage{Value: value})
+		s.assert.NoError(err)
+	}
+
+	dlq := s.broker.DeadLetterQueue(subscription)
+
+	s.assert.Eventually(func() bool {
+		messages, _ := dlq.List(context.Background(), 10)
+		return len(messages) == 2
+	}, time.Second, time.Millisecond)
+
+	// stop the consumer before changing the handler
+	cancel()
+	<-done
+
+	messages, err := dlq.List(context.Background(), 1)
+	s.assert.NoError(err)
+	s.assert.Len(messages, 1)
+	s.assert.Equal(1, messages[0].ReceiveCount)
+	s.assert.JSONEq(`{"value":"first"}`, string(messages[0].Body))
+
+	failing = false
+	go s.broker.Subscriber(subscription).Run(context.Background())
+
+	replayed, err := dlq.Replay(context.Background(), &core.ReplayRequest{
+		MessageIDs: []string{messages[0].ID},
+		Target:     core.ReplayToQueue,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(1, replayed)
+	s.assert.Equal("first", <-handled)
+
+	s.assert.NoError(dlq.Purge(context.Background()))
+	s.assert.Empty(s.broker.DeadLetters("replayed"))
+}
+
+func (s *memoryBrokerTestSuite) TestFilterPolicy() {
+	received := make(chan *core.Message, 2)
+
+	subscriber := s.broker.Subscriber(&core.Subscription{
+		SubscriberID: "completed",
+		TopicID:      "topic",
+		FilterPolicy: core.FilterPolicy{core.EventTypeAttribute: {"TradeCompleted"}},
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			received <- message
+			return nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+
+	for _, attributes := range []map[string]string{
+		nil,
+		{core.EventTypeAttribute: "TradeAccepted"},
+		{core.EventTypeAttribute: "TradeCompleted", core.CorrelationIDAttribute: "abc"},
+	} {
+		_, err := s.broker.PublishWithAttributes("topic", &testMessage{Value: "hello"}, attributes)
+		s.assert.NoError(err)
+	}
+
+	message := <-received
+
+	s.assert.Equal("TradeCompleted", message.Attributes[core.EventTypeAttribute])
+	s.assert.Equal("abc", message.CorrelationID)
+	s.assert.Empty(received)
+}
+
+func (s *memoryBrokerTestSuite) TestFIFOKeepsGroupOrderWhileRetrying() {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	handled := make(chan string, 3)
+
+	subscriber := s.broker.Subscriber(&core.Subscription{
+		SubscriberID: "ordered.fifo",
+		TopicID:      "topic.fifo",
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			body := new(testMessage)
+			if err := message.Decode(body); err != nil {
+				return err
+			}
+
+			mu.Lock()
+			attempts[body.Value]++
+			failing := body.Value == "a1" && attempts[body.Value] < 3
+			mu.Unlock()
+
+			if failing {
+				return errors.New("handler failed")
+			}
+
+			handled <- message.GroupID + ":" + body.Value
+			return nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+
+	for _, value := range []string{"a1", "a2", "b1"} {
+		_, err := s.broker.PublishWithOptions("topic.fifo", &testMessage{Value: value}, &core.PublishOptions{
+			GroupID: value[:1],
+		})
+
+		s.assert.NoError(err)
+	}
+
+	s.assert.Equal("b:b1", <-handled)
+	s.assert.Equal("a:a1", <-handled)
+	s.assert.Equal("a:a2", <-handled)
+	s.assert.Equal(1, attempts["a2"])
+}
+
+func (s *memoryBrokerTestSuite) TestFIFOFullQueueReleasesGroup() {
+	broker := core.NewInMemoryBroker(&core.BrokerConfig{RetryDelay: 200 * time.Millisecond, QueueSize: 2})
+
+	failed := make(chan struct{}, 1)
+	started := make(chan struct{})
+	gate := make(chan struct{})
+	handled := make(chan string, 4)
+
+	subscriber := broker.Subscriber(&core.Subscription{
+		SubscriberID: "ordered.fifo",
+		TopicID:      "topic.fifo",
+		Handler: core.HandlerFunc(func(ctx context.Context, message *core.Message) error {
+			body := new(testMessage)
+			if err := message.Decode(body); err != nil {
+				return err
+			}
+
+			switch body.Value {
+			case "a1":
+				failed <- struct{}{}
+				return errors.New("handler failed")
+			case "b1":
+				close(started)
+				<-gate
+			}
+
+			handled <- body.Value
+			return nil
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+
+	publish := func(value string) {
+		_, err := broker.PublishWithOptions("topic.fifo", &testMessage{Value: value}, &core.PublishOptions{
+			GroupID: value[:1],
+		})
+
+		s.assert.NoError(err)
+	}
+
+	// a1 fails and blocks its group, a2 is held behind it
+	publish("a1")
+	<-failed
+	publish("a2")
+
+	// the subscriber is busy with b1 while b2 and b3 fill the queue, so the
+	// retry of a1 and then a2 are dead lettered
+	publish("b1")
+	<-started
+	publish("b2")
+	publish("b3")
+
+	s.assert.Eventually(func() bool {
+		return len(broker.DeadLetters("ordered.fifo")) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	close(gate)
+
+	for _, value := range []string{"b1", "b2", "b3"} {
+		s.assert.Equal(value, <-handled)
+	}
+
+	publish("a3")
+
+	select {
+	case value := <-handled:
+		s.assert.Equal("a3", value)
+	case <-time.After(time.Second):
+		s.Fail("group a is still blocked")
+	}
+}
+
+func (s *memoryBrokerTestSuite) TestFIFOPublishOptions() {
+	_, err := s.broker.Publish("topic.fifo", &testMessage{Value: "hello"})
+	s.assert.Error(err)
+
+	opts := &core.PublishOptions{GroupID: "trade", DeduplicationID: "event"}
+
+	first, err := s.broker.PublishWithOptions("topic.fifo", &testMessage{Value: "hello"}, opts)
+	s.assert.NoError(err)
+
+	second, err := s.broker.PublishWithOptions("topic.fifo", &testMessage{Value: "hello"}, opts)
+	s.assert.NoError(err)
+	s.assert.Equal(first, second)
+}
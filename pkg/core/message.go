@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// CorrelationIDAttribute message attribute carrying the correlation id
+	CorrelationIDAttribute = "correlation_id"
+)
+
+// ErrMalformedMessage returned when a message payload cannot be decoded. sqs
+// makes malformed messages visible again right away without backoff, they
+// reach the dead letter queue through the redrive policy once received max
+// receive count times. The in memory broker dead letters them at once
+var ErrMalformedMessage = errors.New("malformed message")
+
+// Message a consumed message and its metadata, Envelope is only set for
+// messages unwrapped by HandlerEnvelope and GroupID for FIFO queues
+type Message struct {
+	ID            string
+	Body          []byte
+	Attributes    map[string]string
+	ReceiveCount  int
+	CorrelationID string
+	GroupID       string
+	Envelope      *Envelope
+}
+
+// Decode unmarshals the message body into v, failures wrap ErrMalformedMessage
+func (m *Message) Decode(v interface{}) error {
+	if err := json.Unmarshal(m.Body, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	return nil
+}
+
+// Handler handles a consumed message, returning an error retries it
+type Handler interface {
+	Handle(ctx context.Context, message *Message) error
+}
+
+// HandlerFunc adapts a function to a Handler
+type HandlerFunc func(ctx context.Context, message *Message) error
+
+// Handle ...
+func (f HandlerFunc) Handle(ctx context.Context, message *Message) error {
+	return f(ctx, message)
+}
+
+// Middleware wraps a handler to run code around every message
+type Middleware func(Handler) Handler
+
+// Chain wraps the handler so the first middleware is the outermost one
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+type snsEnvelope struct {
+	Type              string                   `json:"Type"`
+	MessageID         string                   `json:"MessageId"`
+	Message           string                   `json:"Message"`
+	MessageAttributes map[string]*snsAttribute `json:"MessageAttributes"`
+}
+
+type snsAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// decodeSNSMessage unwraps the notification SNS delivers to SQS, bodies that
+// are not notifications were sent with raw delivery and are used as they are
+func decodeSNSMessage(sqsMessageID string, body []byte) *Message {
+	message := &Message{
+		ID:         sqsMessageID,
+		Body:       body,
+		Attributes: map[string]string{},
+	}
+
+	envelope := new(snsEnvelope)
+
+	if err := json.Unmarshal(body, envelope); err != nil || envelope.Type != "Notification" {
+		return message
+	}
+
+	message.ID = envelope.MessageID
+	message.Body = []byte(envelope.Message)
+
+	for name, attribute := range envelope.MessageAttributes {
+		if attribute != nil {
+			message.Attributes[name] = attribute.Value
+		}
+	}
+
+	message.CorrelationID = message.Attributes[CorrelationIDAttribute]
+
+	return message
+}
@@ -1,40 +1,75 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
-	"strings"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 )
 
 // MessageBrokerProducer ...
 type MessageBrokerProducer struct {
-	snsSvc *sns.SNS
+	snsSvc   snsiface.SNSAPI
+	resolver *Resolver
+}
+
+// MessageBrokerProducerOption ...
+type MessageBrokerProducerOption func(*MessageBrokerProducer)
+
+// WithProducerResolver - default resolves topics with the session of the producer
+func WithProducerResolver(resolver *Resolver) MessageBrokerProducerOption {
+	return func(p *MessageBrokerProducer) {
+		p.resolver = resolver
+	}
 }
 
 // NewMessageBrokerProducer ...
-func NewMessageBrokerProducer(s *session.Session) *MessageBrokerProducer {
-	snsSvc := sns.New(s)
-	return &MessageBrokerProducer{snsSvc: snsSvc}
+func NewMessageBrokerProducer(s *session.Session, opts ...MessageBrokerProducerOption) *MessageBrokerProducer {
+	producer := &MessageBrokerProducer{snsSvc: sns.New(s)}
+
+	for _, opt := range opts {
+		opt(producer)
+	}
+
+	if producer.resolver == nil {
+		producer.resolver = NewResolver(s, "")
+	}
+
+	return producer
 }
 
 // Publish ...
 func (p *MessageBrokerProducer) Publish(topicID string, data interface{}) (string, error) {
-	messageID, err := p.PublishWihAttribrutes(topicID, data, nil)
-	return messageID, err
+	return p.PublishWithAttributes(topicID, data, nil)
 }
 
 // PublishWihAttribrutes ...
+//
+// Deprecated: use PublishWithAttributes
 func (p *MessageBrokerProducer) PublishWihAttribrutes(topicID string, data interface{}, attributes map[string]string) (string, error) {
+	return p.PublishWithAttributes(topicID, data, attributes)
+}
+
+// PublishWithAttributes the attributes are sent as SNS string message
+// attributes, subscription filter policies are matched against them
+func (p *MessageBrokerProducer) PublishWithAttributes(topicID string, data interface{}, attributes map[string]string) (string, error) {
+	return p.PublishWithOptions(topicID, data, &PublishOptions{Attributes: attributes})
+}
+
+// PublishWithOptions FIFO topics require a group id and, unless content
+// based deduplication is enabled on the topic, a deduplication id
+func (p *MessageBrokerProducer) PublishWithOptions(topicID string, data interface{}, opts *PublishOptions) (string, error) {
 	body, err := json.Marshal(data)
 
 	if err != nil {
 		return "", err
 	}
 
-	topic, err := createTopicIfNotExists(p.snsSvc, topicID)
+	topicARN, err := p.resolver.TopicARN(context.Background(), topicID)
 
 	if err != nil {
 		return "", err
@@ -42,10 +77,26 @@ func (p *MessageBrokerProducer) PublishWihAttribrutes(topicID string, data inter
 
 	message := string(body)
 
-	output, err := p.snsSvc.Publish(&sns.PublishInput{
-		Message:  &message,
-		TopicArn: topic,
-	})
+	input := &sns.PublishInput{
+		Message:           &message,
+		MessageAttributes: toMessageAttributes(opts.Attributes),
+		TopicArn:          aws.String(topicARN),
+	}
+
+	// standard topics reject group and deduplication ids
+	if IsFIFO(topicID) {
+		if opts.GroupID == "" {
+			return "", fmt.Errorf("publishing to FIFO topic %s requires a group id", topicID)
+		}
+
+		input.MessageGroupId = aws.String(opts.GroupID)
+
+		if opts.DeduplicationID != "" {
+			input.MessageDeduplicationId = aws.String(opts.DeduplicationID)
+		}
+	}
+
+	output, err := p.snsSvc.Publish(input)
 
 	if err != nil {
 		return "", err
@@ -55,31 +106,24 @@ func (p *MessageBrokerProducer) PublishWihAttribrutes(topicID string, data inter
 
 }
 
-func createTopicIfNotExists(snsSvc *sns.SNS, id string) (*string, error) {
-	var topicArn *string
-
-	allTopics, _ := snsSvc.ListTopics(&sns.ListTopicsInput{})
-
-	for _, t := range allTopics.Topics {
-		splitTopic := strings.Split(*t.TopicArn, ":")
-		strTopic := splitTopic[len(splitTopic)-1]
-		if strings.Compare(strTopic, id) == 0 {
-			topicArn = t.TopicArn
-			break
-		}
+// toMessageAttributes sns rejects attributes with empty values so they are skipped
+func toMessageAttributes(attributes map[string]string) map[string]*sns.MessageAttributeValue {
+	if len(attributes) == 0 {
+		return nil
 	}
 
-	if topicArn != nil {
-		return topicArn, nil
-	}
+	result := map[string]*sns.MessageAttributeValue{}
 
-	topic, err := snsSvc.CreateTopic(&sns.CreateTopicInput{
-		Name: aws.String(id),
-	})
+	for name, value := range attributes {
+		if value == "" {
+			continue
+		}
 
-	if err != nil {
-		return nil, err
+		result[name] = &sns.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
 	}
 
-	return topic.TopicArn, nil
+	return result
 }
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// Manifest declares the topics and subscriptions to provision
+type Manifest struct {
+	Topics        []string                   `yaml:"topics"`
+	Subscriptions []*SubscriptionDeclaration `yaml:"subscriptions"`
+}
+
+// SubscriptionDeclaration a queue subscribed to a topic, messages failing
+// max retries times are moved to its dead letter queue
+type SubscriptionDeclaration struct {
+	SubscriberID string       `yaml:"subscriber_id"`
+	TopicID      string       `yaml:"topic_id"`
+	MaxRetries   int          `yaml:"max_retries"`
+	FilterPolicy FilterPolicy `yaml:"filter_policy"`
+}
+
+// Provisioner creates the topics, queues, dead letter queues and subscriptions
+// that do not exist yet and updates the policies of the existing ones, so it
+// can run any number of times
+type Provisioner struct {
+	resolver *Resolver
+}
+
+type queuePolicy struct {
+	Version   string                  `json:"Version"`
+	ID        string                  `json:"Id"`
+	Statement []*queuePolicyStatement `json:"Statement"`
+}
+
+type queuePolicyStatement struct {
+	Effect    string                       `json:"Effect"`
+	Principal map[string]string            `json:"Principal"`
+	Action    string                       `json:"Action"`
+	Resource  string                       `json:"Resource"`
+	Condition map[string]map[string]string `json:"Condition"`
+}
+
+// NewProvisioner ...
+func NewProvisioner(resolver *Resolver) *Provisioner {
+	return &Provisioner{resolver: resolver}
+}
+
+// Provision ...
+func (p *Provisioner) Provision(ctx context.Context, manifest *Manifest) error {
+	for _, topicID := range manifest.Topics {
+		if _, err := p.resolver.TopicARN(ctx, topicID); err != nil {
+			return err
+		}
+	}
+
+	for _, declaration := range manifest.Subscriptions {
+		if _, err := p.EnsureSubscription(ctx, declaration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnsureSubscription returns the url of the queue of the subscriber
+func (p *Provisioner) EnsureSubscription(ctx context.Context, declaration *SubscriptionDeclaration) (string, error) {
+	maxRetries := declaration.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = defaultMaxRetries
+	}
+
+	// sns only delivers to FIFO queues from FIFO topics and the other way around
+	if IsFIFO(declaration.TopicID) != IsFIFO(declaration.SubscriberID) {
+		return "", fmt.Errorf("subscriber %s and topic %s must both be FIFO or neither", declaration.SubscriberID, declaration.TopicID)
+	}
+
+	topicARN, err := p.resolver.TopicARN(ctx, declaration.TopicID)
+	if err != nil {
+		return "", err
+	}
+
+	dlqName := DeadLetterQueueName(declaration.SubscriberID)
+
+	if _, err := p.resolver.EnsureQueue(ctx, dlqName, queueAttributes(dlqName)); err != nil {
+		return "", err
+	}
+
+	dlqARN, err := p.resolver.QueueARN(ctx, dlqName)
+	if err != nil {
+		return "", err
+	}
+
+	queueURL, err := p.resolver.EnsureQueue(ctx, declaration.SubscriberID, queueAttributes(declaration.SubscriberID))
+	if err != nil {
+		return "", err
+	}
+
+	queueARN, err := p.resolver.QueueARN(ctx, declaration.SubscriberID)
+	if err != nil {
+		return "", err
+	}
+
+	policy, err := json.Marshal(&queuePolicy{
+		Version: "2012-10-17",
+		ID:      queueARN + "/SQSDefaultPolicy",
+		Statement: []*queuePolicyStatement{
+			{
+				Effect:    "Allow",
+				Principal: map[string]string{"AWS": "*"},
+				Action:    "SQS:SendMessage",
+				Resource:  queueARN,
+				Condition: map[string]map[string]string{"ArnEquals": {"aws:SourceArn": topicARN}},
+			},
+		},
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	redrivePolicy, err := json.Marshal(map[string]string{
+		"deadLetterTargetArn": dlqARN,
+		"maxReceiveCount":     strconv.Itoa(maxRetries),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	_, err = p.resolver.sqsSvc.SetQueueAttributesWithContext(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: map[string]*string{
+			sqs.QueueAttributeNamePolicy:        aws.String(string(policy)),
+			sqs.QueueAttributeNameRedrivePolicy: aws.String(string(redrivePolicy)),
+		},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error setting attributes of queue %s: %w", declaration.SubscriberID, err)
+	}
+
+	if err := p.ensureTopicSubscription(ctx, topicARN, queueARN, declaration.FilterPolicy); err != nil {
+		return "", fmt.Errorf("error subscribing %s to topic %s: %w", declaration.SubscriberID, declaration.TopicID, err)
+	}
+
+	return queueURL, nil
+}
+
+// ensureTopicSubscription the filter policy of an existing subscription is
+// replaced since it may have changed since it was created
+func (p *Provisioner) ensureTopicSubscription(ctx context.Context, topicARN, queueARN string, filterPolicy FilterPolicy) error {
+	var policy *string
+
+	if len(filterPolicy) > 0 {
+		content, err := filterPolicy.JSON()
+		if err != nil {
+			return err
+		}
+
+		policy = aws.String(content)
+	}
+
+	var subscriptionARN *string
+
+	err := p.resolver.snsSvc.ListSubscriptionsByTopicPagesWithContext(ctx, &sns.ListSubscriptionsByTopicInput{TopicArn: aws.String(topicARN)},
+		func(page *sns.ListSubscriptionsByTopicOutput, lastPage bool) bool {
+			for _, subscription := range page.Subscriptions {
+				if aws.StringValue(subscription.Endpoint) == queueARN {
+					subscriptionARN = subscription.SubscriptionArn
+					return false
+				}
+			}
+
+			return true
+		})
+
+	if err != nil {
+		return err
+	}
+
+	if subscriptionARN == nil {
+		input := &sns.SubscribeInput{
+			TopicArn: aws.String(topicARN),
+			Protocol: aws.String("sqs"),
+			Endpoint: aws.String(queueARN),
+		}
+
+		if policy != nil {
+			input.Attributes = map[string]*string{"FilterPolicy": policy}
+		}
+
+		_, err := p.resolver.snsSvc.SubscribeWithContext(ctx, input)
+
+		return err
+	}
+
+	// an empty policy removes the one a previous declaration set
+	if policy == nil {
+		policy = aws.String("{}")
+	}
+
+	_, err = p.resolver.snsSvc.SetSubscriptionAttributesWithContext(ctx, &sns.SetSubscriptionAttributesInput{
+		SubscriptionArn: subscriptionARN,
+		AttributeName:   aws.String("FilterPolicy"),
+		AttributeValue:  policy,
+	})
+
+	return err
+}
+
+// queueAttributes FIFO queues only deliver the next message of a group once
+// the previous one is deleted
+func queueAttributes(name string) map[string]*string {
+	if !IsFIFO(name) {
+		return nil
+	}
+
+	return map[string]*string{sqs.QueueAttributeNameFifoQueue: aws.String("true")}
+}
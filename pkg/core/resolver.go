@@ -0,0 +1,198 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// ErrQueueNotFound returned when resolving a queue that was never created
+var ErrQueueNotFound = errors.New("queue not found")
+
+// Resolver finds topic ARNs and queue URLs and caches them for the lifetime of
+// the process, they never change once created
+type Resolver struct {
+	snsSvc    snsiface.SNSAPI
+	sqsSvc    sqsiface.SQSAPI
+	stsSvc    stsiface.STSAPI
+	region    string
+	accountID string
+	mu        sync.Mutex
+	topics    map[string]string
+	queues    map[string]string
+}
+
+// NewResolver an empty account id is looked up with sts when first needed
+func NewResolver(s *session.Session, accountID string) *Resolver {
+	return newResolver(sns.New(s), sqs.New(s), sts.New(s), aws.StringValue(s.Config.Region), accountID)
+}
+
+func newResolver(snsSvc snsiface.SNSAPI, sqsSvc sqsiface.SQSAPI, stsSvc stsiface.STSAPI, region, accountID string) *Resolver {
+	return &Resolver{
+		snsSvc:    snsSvc,
+		sqsSvc:    sqsSvc,
+		stsSvc:    stsSvc,
+		region:    region,
+		accountID: accountID,
+		topics:    map[string]string{},
+		queues:    map[string]string{},
+	}
+}
+
+// TopicARN creates the topic when no page of topics has it, names ending
+// with FIFOSuffix create FIFO topics
+func (r *Resolver) TopicARN(ctx context.Context, name string) (string, error) {
+	if topicARN, cached := r.cached(r.topics, name); cached {
+		return topicARN, nil
+	}
+
+	var topicARN string
+
+	err := r.snsSvc.ListTopicsPagesWithContext(ctx, &sns.ListTopicsInput{},
+		func(page *sns.ListTopicsOutput, lastPage bool) bool {
+			for _, topic := range page.Topics {
+				parsed, err := arn.Parse(aws.StringValue(topic.TopicArn))
+				if err == nil && parsed.Resource == name {
+					topicARN = parsed.String()
+					return false
+				}
+			}
+
+			return true
+		})
+
+	if err != nil {
+		return "", fmt.Errorf("error listing topics: %w", err)
+	}
+
+	if topicARN == "" {
+		input := &sns.CreateTopicInput{Name: aws.String(name)}
+		if IsFIFO(name) {
+			input.Attributes = map[string]*string{"FifoTopic": aws.String("true")}
+		}
+
+		output, err := r.snsSvc.CreateTopicWithContext(ctx, input)
+		if err != nil {
+			return "", fmt.Errorf("error creating topic %s: %w", name, err)
+		}
+
+		topicARN = aws.StringValue(output.TopicArn)
+	}
+
+	r.cache(r.topics, name, topicARN)
+
+	return topicARN, nil
+}
+
+// QueueURL returns ErrQueueNotFound when the queue does not exist
+func (r *Resolver) QueueURL(ctx context.Context, name string) (string, error) {
+	if queueURL, cached := r.cached(r.queues, name); cached {
+		return queueURL, nil
+	}
+
+	output, err := r.sqsSvc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == sqs.ErrCodeQueueDoesNotExist {
+		return "", fmt.Errorf("%w: %s", ErrQueueNotFound, name)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error getting queue %s: %w", name, err)
+	}
+
+	r.cache(r.queues, name, aws.StringValue(output.QueueUrl))
+
+	return aws.StringValue(output.QueueUrl), nil
+}
+
+// EnsureQueue creates the queue with the attributes when it does not exist
+func (r *Resolver) EnsureQueue(ctx context.Context, name string, attributes map[string]*string) (string, error) {
+	queueURL, err := r.QueueURL(ctx, name)
+	if !errors.Is(err, ErrQueueNotFound) {
+		return queueURL, err
+	}
+
+	output, err := r.sqsSvc.CreateQueueWithContext(ctx, &sqs.CreateQueueInput{
+		QueueName:  aws.String(name),
+		Attributes: attributes,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error creating queue %s: %w", name, err)
+	}
+
+	r.cache(r.queues, name, aws.StringValue(output.QueueUrl))
+
+	return aws.StringValue(output.QueueUrl), nil
+}
+
+// QueueARN builds the arn of the queue from the partition, region and account
+func (r *Resolver) QueueARN(ctx context.Context, name string) (string, error) {
+	accountID, err := r.account(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	partition := endpoints.AwsPartitionID
+	if p, exists := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), r.region); exists {
+		partition = p.ID()
+	}
+
+	return arn.ARN{
+		Partition: partition,
+		Service:   sqs.ServiceName,
+		Region:    r.region,
+		AccountID: accountID,
+		Resource:  name,
+	}.String(), nil
+}
+
+func (r *Resolver) account(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	accountID := r.accountID
+	r.mu.Unlock()
+
+	if accountID != "" {
+		return accountID, nil
+	}
+
+	output, err := r.stsSvc.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("error getting aws account: %w", err)
+	}
+
+	r.mu.Lock()
+	r.accountID = aws.StringValue(output.Account)
+	r.mu.Unlock()
+
+	return aws.StringValue(output.Account), nil
+}
+
+func (r *Resolver) cached(cache map[string]string, name string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	value, exists := cache[name]
+	return value, exists
+}
+
+func (r *Resolver) cache(cache map[string]string, name, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cache[name] = value
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeSNS returns one topic per page and records the subscriptions
+type fakeSNS struct {
+	snsiface.SNSAPI
+	topics        []string
+	listCalls     int
+	created       []string
+	subscriptions []*sns.SubscribeInput
+	policies      map[string]string
+}
+
+func (f *fakeSNS) ListTopicsPagesWithContext(ctx aws.Context, input *sns.ListTopicsInput, fn func(*sns.ListTopicsOutput, bool) bool, opts ...request.Option) error {
+	f.listCalls++
+
+	for i, topic := range f.topics {
+		page := &sns.ListTopicsOutput{Topics: []*sns.Topic{{TopicArn: aws.String(topic)}}}
+		if !fn(page, i == len(f.topics)-1) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeSNS) CreateTopicWithContext(ctx aws.Context, input *sns.CreateTopicInput, opts ...request.Option) (*sns.CreateTopicOutput, error) {
+	f.created = append(f.created, *input.Name)
+
+	return &sns.CreateTopicOutput{TopicArn: aws.String("arn:aws:sns:us-west-2:123456789012:" + *input.Name)}, nil
+}
+
+func (f *fakeSNS) ListSubscriptionsByTopicPagesWithContext(ctx aws.Context, input *sns.ListSubscriptionsByTopicInput, fn func(*sns.ListSubscriptionsByTopicOutput, bool) bool, opts ...request.Option) error {
+	page := &sns.ListSubscriptionsByTopicOutput{}
+
+	for i, subscription := range f.subscriptions {
+		page.Subscriptions = append(page.Subscriptions, &sns.Subscription{
+			SubscriptionArn: aws.String(string(rune('a' + i))),
+			Endpoint:        subscription.Endpoint,
+		})
+	}
+
+	fn(page, true)
+
+	return nil
+}
+
+func (f *fakeSNS) SubscribeWithContext(ctx aws.Context, input *sns.SubscribeInput, opts ...request.Option) (*sns.SubscribeOutput, error) {
+	f.subscriptions = append(f.subscriptions, input)
+
+	return &sns.SubscribeOutput{}, nil
+}
+
+func (f *fakeSNS) SetSubscriptionAttributesWithContext(ctx aws.Context, input *sns.SetSubscriptionAttributesInput, opts ...request.Option) (*sns.SetSubscriptionAttributesOutput, error) {
+	f.policies[*input.SubscriptionArn] = *input.AttributeValue
+
+	return &sns.SetSubscriptionAttributesOutput{}, nil
+}
+
+// fakeQueues keeps the created queues and their attributes
+type fakeQueues struct {
+	sqsiface.SQSAPI
+	queues     map[string]map[string]*string
+	getQueries int
+}
+
+func (f *fakeQueues) GetQueueUrlWithContext(ctx aws.Context, input *sqs.GetQueueUrlInput, opts ...request.Option) (*sqs.GetQueueUrlOutput, error) {
+	f.getQueries++
+
+	if _, exists := f.queues[*input.QueueName]; !exists {
+		return nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "queue does not exist", nil)
+	}
+
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String("https://sqs/" + *input.QueueName)}, nil
+}
+
+func (f *fakeQueues) CreateQueueWithContext(ctx aws.Context, input *sqs.CreateQueueInput, opts ...request.Option) (*sqs.CreateQueueOutput, error) {
+	f.queues[*input.QueueName] = map[string]*string{}
+
+	return &sqs.CreateQueueOutput{QueueUrl: aws.String("https://sqs/" + *input.QueueName)}, nil
+}
+
+func (f *fakeQueues) SetQueueAttributesWithContext(ctx aws.Context, input *sqs.SetQueueAttributesInput, opts ...request.Option) (*sqs.SetQueueAttributesOutput, error) {
+	name := (*input.QueueUrl)[len("https://sqs/"):]
+
+	for key, value := range input.Attributes {
+		f.queues[name][key] = value
+	}
+
+	return &sqs.SetQueueAttributesOutput{}, nil
+}
+
+type fakeSTS struct {
+	stsiface.STSAPI
+	calls int
+}
+
+func (f *fakeSTS) GetCallerIdentityWithContext(ctx aws.Context, input *sts.GetCallerIdentityInput, opts ...request.Option) (*sts.GetCallerIdentityOutput, error) {
+	f.calls++
+
+	return &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil
+}
+
+type resolverTestSuite struct {
+	suite.Suite
+	assert   *assert.Assertions
+	ctx      context.Context
+	sns      *fakeSNS
+	sqs      *fakeQueues
+	sts      *fakeSTS
+	resolver *Resolver
+}
+
+func TestResolverTestSuite(t *testing.T) {
+	suite.Run(t, new(resolverTestSuite))
+}
+
+func (s *resolverTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *resolverTestSuite) SetupTest() {
+	s.sns = &fakeSNS{
+		topics: []string{
+			"arn:aws:sns:us-west-2:123456789012:trade-updated-old",
+			"arn:aws:sns:us-west-2:123456789012:trade-updated",
+		},
+		policies: map[string]string{},
+	}
+	s.sqs = &fakeQueues{queues: map[string]map[string]*string{}}
+	s.sts = &fakeSTS{}
+	s.resolver = newResolver(s.sns, s.sqs, s.sts, "us-west-2", "")
+}
+
+func (s *resolverTestSuite) TestTopicARNSearchesEveryPageOnce() {
+	for i := 0; i < 2; i++ {
+		topicARN, err := s.resolver.TopicARN(s.ctx, "trade-updated")
+
+		s.assert.NoError(err)
+		s.assert.Equal("arn:aws:sns:us-west-2:123456789012:trade-updated", topicARN)
+	}
+
+	s.assert.Equal(1, s.sns.listCalls)
+	s.assert.Empty(s.sns.created)
+}
+
+func (s *resolverTestSuite) TestTopicARNCreatesMissingTopic() {
+	topicARN, err := s.resolver.TopicARN(s.ctx, "trade-completed")
+
+	s.assert.NoError(err)
+	s.assert.Equal("arn:aws:sns:us-west-2:123456789012:trade-completed", topicARN)
+	s.assert.Equal([]string{"trade-completed"}, s.sns.created)
+}
+
+func (s *resolverTestSuite) TestQueueURLNotFound() {
+	_, err := s.resolver.QueueURL(s.ctx, "missing")
+
+	s.assert.ErrorIs(err, ErrQueueNotFound)
+}
+
+func (s *resolverTestSuite) TestQueueARN() {
+	expected := map[string]string{
+		"us-west-2":  "arn:aws:sqs:us-west-2:123456789012:subscriber",
+		"cn-north-1": "arn:aws-cn:sqs:cn-north-1:123456789012:subscriber",
+	}
+
+	for region, arn := range expected {
+		s.resolver.region = region
+
+		queueARN, err := s.resolver.QueueARN(s.ctx, "subscriber")
+
+		s.assert.NoError(err)
+		s.assert.Equal(arn, queueARN)
+	}
+
+	s.assert.Equal(1, s.sts.calls)
+}
+
+func (s *resolverTestSuite) TestEnsureSubscription() {
+	declaration := &SubscriptionDeclaration{
+		SubscriberID: "subscriber",
+		TopicID:      "trade-updated",
+		MaxRetries:   3,
+		FilterPolicy: FilterPolicy{EventTypeAttribute: {"trade-offer-updated"}},
+	}
+
+	provisioner := NewProvisioner(s.resolver)
+
+	for i := 0; i < 2; i++ {
+		queueURL, err := provisioner.EnsureSubscription(s.ctx, declaration)
+
+		s.assert.NoError(err)
+		s.assert.Equal("https://sqs/subscriber", queueURL)
+	}
+
+	s.assert.Contains(s.sqs.queues, "subscriber_dlq")
+	s.assert.Len(s.sns.subscriptions, 1)
+	s.assert.Equal("arn:aws:sqs:us-west-2:123456789012:subscriber", *s.sns.subscriptions[0].Endpoint)
+	s.assert.JSONEq(`{"event_type":["trade-offer-updated"]}`, *s.sns.subscriptions[0].Attributes["FilterPolicy"])
+	s.assert.JSONEq(`{"event_type":["trade-offer-updated"]}`, s.sns.policies["a"])
+
+	redrive := map[string]string{}
+	s.assert.NoError(json.Unmarshal([]byte(*s.sqs.queues["subscriber"][sqs.QueueAttributeNameRedrivePolicy]), &redrive))
+	s.assert.Equal("arn:aws:sqs:us-west-2:123456789012:subscriber_dlq", redrive["deadLetterTargetArn"])
+	s.assert.Equal("3", redrive["maxReceiveCount"])
+}
+
+func (s *resolverTestSuite) TestEnsureSubscriptionClearsFilterPolicy() {
+	declaration := &SubscriptionDeclaration{
+		SubscriberID: "subscriber",
+		TopicID:      "trade-updated",
+		FilterPolicy: FilterPolicy{EventTypeAttribute: {"trade-offer-updated"}},
+	}
+
+	provisioner := NewProvisioner(s.resolver)
+
+	_, err := provisioner.EnsureSubscription(s.ctx, declaration)
+	s.assert.NoError(err)
+
+	declaration.FilterPolicy = nil
+
+	_, err = provisioner.EnsureSubscription(s.ctx, declaration)
+	s.assert.NoError(err)
+
+	s.assert.Len(s.sns.subscriptions, 1)
+	s.assert.JSONEq(`{}`, s.sns.policies["a"])
+}
+
+func (s *resolverTestSuite) TestEnsureSubscriptionRejectsFIFOMismatch() {
+	_, err := NewProvisioner(s.resolver).EnsureSubscription(s.ctx, &SubscriptionDeclaration{
+		SubscriberID: "subscriber.fifo",
+		TopicID:      "trade-updated",
+	})
+
+	s.assert.Error(err)
+	s.assert.Empty(s.sqs.queues)
+}
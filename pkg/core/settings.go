@@ -1,11 +1,23 @@
 package core
 
+import "time"
+
 // Settings ...
 type Settings struct {
 	Port             int32          `yaml:"port"`
 	JWT              *JWT           `yaml:"jwt"`
 	MongoDB          *MongoDBConfig `yaml:"mongodb"`
 	InventoryService *GRPCService   `yaml:"inventory_service"`
+	TradeRules       *TradeRules    `yaml:"trade_rules"`
+	Auctions         *Auctions      `yaml:"auctions"`
+	Matching         *Matching      `yaml:"matching"`
+	AWS              *AWS           `yaml:"aws"`
+	Topics           *Topics        `yaml:"topics"`
+	Batch            *Batch         `yaml:"batch"`
+	Stats            *Stats         `yaml:"stats"`
+	Reputation       *Reputation    `yaml:"reputation"`
+	Broker           *BrokerConfig  `yaml:"broker"`
+	Inbox            *InboxConfig   `yaml:"inbox"`
 }
 
 // JWT ...
@@ -23,3 +35,85 @@ type MongoDBConfig struct {
 type GRPCService struct {
 	URL string `yaml:"url"`
 }
+
+// AWS ...
+type AWS struct {
+	Region    string `yaml:"region"`
+	Endpoint  string `yaml:"endpoint"`
+	AccountID string `yaml:"account_id"`
+}
+
+// BrokerConfig Type is either aws or memory, the queue size and retry delay
+// are only used by the in memory broker and the rest only by aws
+type BrokerConfig struct {
+	Type              string        `yaml:"type"`
+	QueueSize         int           `yaml:"queue_size"`
+	RetryDelay        time.Duration `yaml:"retry_delay"`
+	Workers           int           `yaml:"workers"`
+	MaxMessages       int64         `yaml:"max_messages"`
+	WaitTime          time.Duration `yaml:"wait_time"`
+	VisibilityTimeout time.Duration `yaml:"visibility_timeout"`
+}
+
+// InboxConfig records of processed messages expire after the ttl, zero
+// values use the defaults of NewInbox
+type InboxConfig struct {
+	TTL           time.Duration `yaml:"ttl"`
+	Lease         time.Duration `yaml:"lease"`
+	Transactional bool          `yaml:"transactional"`
+}
+
+// Topics ...
+type Topics struct {
+	TradeUpdated         string `yaml:"trade_updated"`
+	InventoryItemChanged string `yaml:"inventory_item_changed"`
+	InventoryItemRemoved string `yaml:"inventory_item_removed"`
+}
+
+// TradeRules limits enforced when creating trade offers, zero disables a limit.
+// MaxOpenOffers and MaxOffersBetweenUsers are soft limits that offers created
+// concurrently can go slightly past
+type TradeRules struct {
+	MaxItemsPerSide       int   `yaml:"max_items_per_side"`
+	MaxItemQuantity       int64 `yaml:"max_item_quantity"`
+	MaxCurrency           int64 `yaml:"max_currency"`
+	MaxOpenOffers         int64 `yaml:"max_open_offers"`
+	MaxOffersBetweenUsers int64 `yaml:"max_offers_between_users"`
+}
+
+// Auctions SettleTimeout is how long an auction may stay closing before the
+// close job settles it again
+type Auctions struct {
+	MaxDuration    time.Duration `yaml:"max_duration"`
+	CloseInterval  time.Duration `yaml:"close_interval"`
+	CloseBatchSize int64         `yaml:"close_batch_size"`
+	SettleTimeout  time.Duration `yaml:"settle_timeout"`
+}
+
+// Matching ...
+type Matching struct {
+	Interval              time.Duration `yaml:"interval"`
+	Cyclic                bool          `yaml:"cyclic"`
+	MaxSuggestionsPerUser int           `yaml:"max_suggestions_per_user"`
+	BatchSize             int64         `yaml:"batch_size"`
+}
+
+// Batch ...
+type Batch struct {
+	MaxIDs      int `yaml:"max_ids"`
+	Concurrency int `yaml:"concurrency"`
+}
+
+// Stats CacheSize caps the cached results, the oldest ones are dropped first
+type Stats struct {
+	CacheTTL  time.Duration `yaml:"cache_ttl"`
+	CacheSize int           `yaml:"cache_size"`
+	TopItems  int64         `yaml:"top_items"`
+}
+
+// Reputation MinScore between 0 and 1 required to create offers, zero disables the check.
+// It only applies to users with at least MinTrades finished trades
+type Reputation struct {
+	MinScore  float64 `yaml:"min_score"`
+	MinTrades int64   `yaml:"min_trades"`
+}
@@ -1,30 +1,46 @@
 package core
 
 import (
-	"encoding/json"
-	"fmt"
-	"reflect"
+	"context"
+	"errors"
 	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultWorkers           = 1
+	defaultVisibilityTimeout = 30 * time.Second
+	handlerTimeoutFactor     = 10
+	maxReceiveMessages       = 10
+	maxWaitTime              = 20 * time.Second
+	receiveErrorBackoff      = time.Second
+)
+
 // MessageBrokerSubscriber ...
 type MessageBrokerSubscriber struct {
-	sqsSvc              *sqs.SQS
-	snsSvc              *sns.SNS
-	handler             func(interface{}) error
+	sqsSvc              sqsiface.SQSAPI
+	session             *session.Session
+	resolver            *Resolver
+	handler             Handler
+	middlewares         []Middleware
 	subscriberID        string
 	topicID             string
-	handleType          reflect.Type
 	maxRetries          int
 	producer            *MessageBrokerProducer
 	maxRetriesAttribute string
+	workers             int
+	maxMessages         int64
+	waitTime            time.Duration
+	visibilityTimeout   time.Duration
+	handlerTimeout      time.Duration
+	filterPolicy        FilterPolicy
 }
 
 // MessageBrokerSubscriberOption ...
@@ -33,7 +49,11 @@ type MessageBrokerSubscriberOption func(*MessageBrokerSubscriber)
 // NewMessageBrokerSubscriber ...
 func NewMessageBrokerSubscriber(opts ...MessageBrokerSubscriberOption) *MessageBrokerSubscriber {
 	subscriber := new(MessageBrokerSubscriber)
-	subscriber.maxRetries = 5
+	subscriber.maxRetries = defaultMaxRetries
+	subscriber.workers = defaultWorkers
+	subscriber.maxMessages = maxReceiveMessages
+	subscriber.waitTime = maxWaitTime
+	subscriber.visibilityTimeout = defaultVisibilityTimeout
 
 	for _, opt := range opts {
 		opt(subscriber)
@@ -46,23 +66,39 @@ func NewMessageBrokerSubscriber(opts ...MessageBrokerSubscriberOption) *MessageB
 func WithSessionSQS(sessionSQS *session.Session) MessageBrokerSubscriberOption {
 	return func(s *MessageBrokerSubscriber) {
 		s.sqsSvc = sqs.New(sessionSQS)
+		s.session = sessionSQS
 	}
 }
 
 // WithSessionSNS ...
+//
+// Deprecated: the SNS client of the resolver is used, it is created from the
+// session of WithSessionSQS when no resolver is set
 func WithSessionSNS(sessionSNS *session.Session) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {}
+}
+
+// WithResolver - default resolves topics and queues with the session of WithSessionSQS
+func WithResolver(resolver *Resolver) MessageBrokerSubscriberOption {
 	return func(s *MessageBrokerSubscriber) {
-		s.snsSvc = sns.New(sessionSNS)
+		s.resolver = resolver
 	}
 }
 
 // WithHandler ...
-func WithHandler(h func(interface{}) error) MessageBrokerSubscriberOption {
+func WithHandler(h Handler) MessageBrokerSubscriberOption {
 	return func(s *MessageBrokerSubscriber) {
 		s.handler = h
 	}
 }
 
+// WithMiddlewares wraps the handler, the first middleware is the outermost one
+func WithMiddlewares(middlewares ...Middleware) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		s.middlewares = append(s.middlewares, middlewares...)
+	}
+}
+
 // WithSubscriberID ...
 func WithSubscriberID(id string) MessageBrokerSubscriberOption {
 	return func(s *MessageBrokerSubscriber) {
@@ -77,13 +113,6 @@ func WithTopicID(t string) MessageBrokerSubscriberOption {
 	}
 }
 
-// WithType ...
-func WithType(t reflect.Type) MessageBrokerSubscriberOption {
-	return func(s *MessageBrokerSubscriber) {
-		s.handleType = t
-	}
-}
-
 // WithMaxRetries - default 5
 func WithMaxRetries(maxRetries int) MessageBrokerSubscriberOption {
 	return func(s *MessageBrokerSubscriber) {
@@ -91,195 +120,394 @@ func WithMaxRetries(maxRetries int) MessageBrokerSubscriberOption {
 	}
 }
 
-// Run ...
-func (s *MessageBrokerSubscriber) Run() error {
-	queueURL, err := createSubscriptionIfNotExists(s.sqsSvc, s.snsSvc, s.subscriberID, s.topicID, s.maxRetries)
+// WithWorkers - default 1, each worker long polls and handles its own batches
+func WithWorkers(workers int) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		if workers > 0 {
+			s.workers = workers
+		}
+	}
+}
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error starting %s", s.subscriberID)
+// WithMaxMessages - default and max 10 per receive
+func WithMaxMessages(maxMessages int64) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		if maxMessages > 0 && maxMessages <= maxReceiveMessages {
+			s.maxMessages = maxMessages
+		}
 	}
+}
 
-	logrus.Infof("starting consumer %s with topic %s", s.subscriberID, s.topicID)
-	return s.checkMessages(s.sqsSvc, queueURL)
+// WithWaitTime - default and max 20s of long polling
+func WithWaitTime(waitTime time.Duration) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		if waitTime > 0 && waitTime <= maxWaitTime {
+			s.waitTime = waitTime
+		}
+	}
 }
 
-func createSubscriptionIfNotExists(sqsSvc *sqs.SQS, snsSvc *sns.SNS, subscriberID, topicID string, maxRetries int) (*string, error) {
-	listQueueResults, err := sqsSvc.ListQueues(&sqs.ListQueuesInput{
-		QueueNamePrefix: aws.String(subscriberID),
-	})
+// WithVisibilityTimeout - default 30s, extended while a batch is being handled
+func WithVisibilityTimeout(visibilityTimeout time.Duration) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		if visibilityTimeout >= time.Second {
+			s.visibilityTimeout = visibilityTimeout
+		}
+	}
+}
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error list queues %s", subscriberID)
-		return nil, err
+// WithHandlerTimeout - default 10 times the visibility timeout, the handler
+// context is cancelled after it and the message is no longer kept hidden
+func WithHandlerTimeout(handlerTimeout time.Duration) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		if handlerTimeout > 0 {
+			s.handlerTimeout = handlerTimeout
+		}
 	}
+}
 
-	var queueURL *string
+// WithFilterPolicy only delivers the messages whose attributes match, it is
+// set on the SNS subscription when the subscriber starts
+func WithFilterPolicy(policy FilterPolicy) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		s.filterPolicy = policy
+	}
+}
 
-	for _, t := range listQueueResults.QueueUrls {
-		parts := strings.Split(*t, "/")
-		if strings.Compare(parts[4], subscriberID) == 0 {
-			queueURL = t
-			break
+// WithSubscription sets the subscriber, topic, handler, middlewares, max retries and filter policy at once
+func WithSubscription(subscription *Subscription) MessageBrokerSubscriberOption {
+	return func(s *MessageBrokerSubscriber) {
+		s.subscriberID = subscription.SubscriberID
+		s.topicID = subscription.TopicID
+		s.handler = subscription.Handler
+		s.middlewares = append(s.middlewares, subscription.Middlewares...)
+		s.filterPolicy = subscription.FilterPolicy
+
+		if subscription.MaxRetries > 0 {
+			s.maxRetries = subscription.MaxRetries
 		}
 	}
+}
 
-	if queueURL != nil {
-		return queueURL, nil
+// Run consumes the queue until the context is cancelled
+func (s *MessageBrokerSubscriber) Run(ctx context.Context) error {
+	if s.resolver == nil {
+		s.resolver = NewResolver(s.session, "")
 	}
 
-	resp, err := sqsSvc.CreateQueue(&sqs.CreateQueueInput{
-		QueueName: aws.String(subscriberID),
+	queueURL, err := NewProvisioner(s.resolver).EnsureSubscription(ctx, &SubscriptionDeclaration{
+		SubscriberID: s.subscriberID,
+		TopicID:      s.topicID,
+		MaxRetries:   s.maxRetries,
+		FilterPolicy: s.filterPolicy,
 	})
 
 	if err != nil {
 		logrus.WithError(err).
-			Errorf("error creating queue %s", subscriberID)
-		return nil, err
+			Errorf("error starting %s", s.subscriberID)
+		return err
 	}
 
-	queueURL = resp.QueueUrl
-	queueARN := convertQueueURLToARN(*queueURL)
+	logrus.Infof("starting consumer %s with topic %s and %d workers", s.subscriberID, s.topicID, s.workers)
 
-	respdlq, err := sqsSvc.CreateQueue(&sqs.CreateQueueInput{
-		QueueName: aws.String(fmt.Sprintf("%s_dlq", subscriberID)),
-	})
+	s.consume(ctx, aws.String(queueURL))
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error creating queue dlq %s", subscriberID)
-		return nil, err
-	}
+	logrus.Infof("consumer %s stopped", s.subscriberID)
 
-	dlqURL := respdlq.QueueUrl
-	dlqARN := convertQueueURLToARN(*dlqURL)
+	return nil
+}
 
-	topicArn, err := createTopicIfNotExists(snsSvc, topicID)
+// consume polls the queue with every worker until the context is cancelled,
+// messages being handled when it happens are still processed and deleted
+func (s *MessageBrokerSubscriber) consume(ctx context.Context, queueURL *string) {
+	var wg sync.WaitGroup
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error creating topic %s", topicID)
-		return nil, err
+	handler := chainHandler(s.handler, s.middlewares)
+
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.poll(ctx, queueURL, handler)
+		}()
 	}
 
-	_, err = snsSvc.Subscribe(&sns.SubscribeInput{
-		TopicArn: topicArn,
-		Protocol: aws.String("sqs"),
-		Endpoint: &queueARN,
-	})
+	wg.Wait()
+}
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error subscribe topic %s", topicID)
-		return nil, err
+func (s *MessageBrokerSubscriber) poll(ctx context.Context, queueURL *string, handler Handler) {
+	for ctx.Err() == nil {
+		retrieveMessageResponse, err := s.sqsSvc.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl: queueURL,
+			AttributeNames: aws.StringSlice([]string{
+				sqs.MessageSystemAttributeNameApproximateReceiveCount,
+				sqs.MessageSystemAttributeNameMessageGroupId,
+			}),
+			MessageAttributeNames: aws.StringSlice([]string{"All"}),
+			MaxNumberOfMessages:   aws.Int64(s.maxMessages),
+			WaitTimeSeconds:       aws.Int64(int64(s.waitTime.Seconds())),
+			VisibilityTimeout:     aws.Int64(s.visibilitySeconds()),
+		})
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			logrus.WithError(err).
+				Errorf("error receive message %s", s.subscriberID)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(receiveErrorBackoff):
+			}
+
+			continue
+		}
+
+		if len(retrieveMessageResponse.Messages) > 0 {
+			s.processMessages(queueURL, retrieveMessageResponse.Messages, handler)
+		}
 	}
+}
+
+// processMessages handles a received batch and deletes only the messages that
+// succeeded, failed ones stop being extended so they become visible again and
+// are retried until redrive. A FIFO batch may hold several messages of a
+// group, once one of them fails the following ones are left to be received
+// again after it
+func (s *MessageBrokerSubscriber) processMessages(queueURL *string, messages []*sqs.Message, handler Handler) {
+	extender := s.extendVisibility(queueURL, messages)
+
+	processed := []*sqs.DeleteMessageBatchRequestEntry{}
+	malformed := []*sqs.ChangeMessageVisibilityBatchRequestEntry{}
+	failedGroups := map[string]bool{}
+
+	for i, mess := range messages {
+		message := decodeSNSMessage(aws.StringValue(mess.MessageId), []byte(aws.StringValue(mess.Body)))
+		message.ReceiveCount, _ = strconv.Atoi(aws.StringValue(mess.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+		message.GroupID = aws.StringValue(mess.Attributes[sqs.MessageSystemAttributeNameMessageGroupId])
+
+		if message.GroupID != "" && failedGroups[message.GroupID] {
+			extender.release(i)
+			continue
+		}
+
+		err := s.handle(handler, message, extender)
+
+		if errors.Is(err, ErrMalformedMessage) {
+			logrus.WithError(err).WithField("content", mess.String()).
+				Errorf("cannot unmarshal message %s - releasing for redrive", *mess.MessageId)
+
+			// visible right away so it reaches the redrive max receive count sooner
+			malformed = append(malformed, &sqs.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(len(malformed))),
+				ReceiptHandle:     mess.ReceiptHandle,
+				VisibilityTimeout: aws.Int64(0),
+			})
+
+			extender.release(i)
+			failedGroups[message.GroupID] = true
+			continue
+		}
+
+		if err != nil {
+			logrus.WithError(err).
+				Errorf("error handling message %s", *mess.MessageId)
+
+			extender.release(i)
+			failedGroups[message.GroupID] = true
+			continue
+		}
 
-	policyContent := "{\"Version\": \"2012-10-17\",  \"Id\": \"" + queueARN + "/SQSDefaultPolicy\",  \"Statement\": [    {     \"Sid\": \"Sid1580665629194\",      \"Effect\": \"Allow\",      \"Principal\": {        \"AWS\": \"*\"      },      \"Action\": \"SQS:SendMessage\",      \"Resource\": \"" + queueARN + "\",      \"Condition\": {        \"ArnEquals\": {         \"aws:SourceArn\": \"" + *topicArn + "\"        }      }    }  ]}"
+		// kept hidden until the batch is deleted
+		extender.succeeded(i)
 
-	policy := map[string]string{
-		"deadLetterTargetArn": dlqARN,
-		"maxReceiveCount":     strconv.Itoa(maxRetries),
+		processed = append(processed, &sqs.DeleteMessageBatchRequestEntry{
+			Id:            mess.MessageId,
+			ReceiptHandle: mess.ReceiptHandle,
+		})
 	}
 
-	redrivePolicyContent, err := json.Marshal(policy)
+	extender.stop()
 
-	if err != nil {
-		logrus.WithError(err).
-			Errorf("error marshal redrive policy %s", subscriberID)
-		return nil, err
+	if len(malformed) > 0 {
+		_, err := s.sqsSvc.ChangeMessageVisibilityBatch(&sqs.ChangeMessageVisibilityBatchInput{
+			QueueUrl: queueURL,
+			Entries:  malformed,
+		})
+
+		if err != nil {
+			logrus.WithError(err).
+				Errorf("error releasing malformed messages %s", s.subscriberID)
+		}
 	}
 
-	setQueueAttrInput := sqs.SetQueueAttributesInput{
-		QueueUrl: queueURL,
-		Attributes: map[string]*string{
-			sqs.QueueAttributeNamePolicy:        aws.String(policyContent),
-			sqs.QueueAttributeNameRedrivePolicy: aws.String(string(redrivePolicyContent)),
-		},
+	if len(processed) == 0 {
+		return
 	}
 
-	_, err = sqsSvc.SetQueueAttributes(&setQueueAttrInput)
+	output, err := s.sqsSvc.DeleteMessageBatch(&sqs.DeleteMessageBatchInput{
+		QueueUrl: queueURL,
+		Entries:  processed,
+	})
 
 	if err != nil {
 		logrus.WithError(err).
-			Errorf("error set attributes policy queue %s", subscriberID)
-		return nil, err
+			Errorf("error deleting messages %s", s.subscriberID)
+		return
 	}
 
-	return queueURL, nil
+	for _, failed := range output.Failed {
+		logrus.WithField("code", aws.StringValue(failed.Code)).
+			Errorf("error deleting message %s: %s", aws.StringValue(failed.Id), aws.StringValue(failed.Message))
+	}
 }
 
-func (s *MessageBrokerSubscriber) checkMessages(sqsSvc *sqs.SQS, queueURL *string) error {
-	for {
-		retrieveMessageRequest := sqs.ReceiveMessageInput{
-			QueueUrl: queueURL,
-		}
+// handle runs the handler with a deadline of the handler timeout, in flight
+// messages are finished even when the consumer is stopping
+func (s *MessageBrokerSubscriber) handle(handler Handler, message *Message, extender *visibilityExtender) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
 
-		retrieveMessageResponse, err := sqsSvc.ReceiveMessage(&retrieveMessageRequest)
+	deadline, _ := ctx.Deadline()
+	extender.handling(deadline)
 
-		if err != nil {
-			logrus.WithError(err).
-				Errorf("error receive message")
-			return err
-		}
+	return handler.Handle(ctx, message)
+}
 
-		if len(retrieveMessageResponse.Messages) > 0 {
+// visibilityExtender keeps the messages of a batch hidden from other consumers
+// while they wait or are handled
+type visibilityExtender struct {
+	mu       sync.Mutex
+	entries  map[int]*sqs.ChangeMessageVisibilityBatchRequestEntry
+	handled  map[int]bool
+	deadline time.Time
+	done     chan struct{}
+	stopped  chan struct{}
+}
 
-			processedReceiptHandles := make([]*sqs.DeleteMessageBatchRequestEntry, len(retrieveMessageResponse.Messages))
+// extendVisibility starts extending the batch until stop is called
+func (s *MessageBrokerSubscriber) extendVisibility(queueURL *string, messages []*sqs.Message) *visibilityExtender {
+	extender := &visibilityExtender{
+		entries: make(map[int]*sqs.ChangeMessageVisibilityBatchRequestEntry, len(messages)),
+		handled: make(map[int]bool, len(messages)),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
 
-			for i, mess := range retrieveMessageResponse.Messages {
-				byt := []byte(*mess.Body)
-				body := reflect.New(reflect.TypeOf(map[string]interface{}{})).Interface()
-				json.Unmarshal(byt, body)
+	for i, mess := range messages {
+		extender.entries[i] = &sqs.ChangeMessageVisibilityBatchRequestEntry{
+			Id:                aws.String(strconv.Itoa(i)),
+			ReceiptHandle:     mess.ReceiptHandle,
+			VisibilityTimeout: aws.Int64(s.visibilitySeconds()),
+		}
+	}
 
-				value := body.(*map[string]interface{})
-				result := (*value)["Message"]
-				messageStr := fmt.Sprintf("%v", result)
+	go func() {
+		defer close(extender.stopped)
 
-				bytMessage := []byte(messageStr)
-				bodyMessage := reflect.New(s.handleType).Interface()
-				err := json.Unmarshal(bytMessage, bodyMessage)
+		ticker := time.NewTicker(s.visibilityTimeout / 2)
+		defer ticker.Stop()
 
-				if err != nil {
-					logrus.WithError(err).WithField("content", mess.String()).
-						Errorf("cannot unmarshal message %s - sending to dlq", *mess.MessageId)
-
-					processedReceiptHandles[i] = &sqs.DeleteMessageBatchRequestEntry{
-						Id:            mess.MessageId,
-						ReceiptHandle: mess.ReceiptHandle,
-					}
-				} else {
-					err = s.handler(bodyMessage)
-
-					if err == nil {
-						processedReceiptHandles[i] = &sqs.DeleteMessageBatchRequestEntry{
-							Id:            mess.MessageId,
-							ReceiptHandle: mess.ReceiptHandle,
-						}
-					}
+		for {
+			select {
+			case <-extender.done:
+				return
+			case <-ticker.C:
+				entries := extender.pending()
+				if len(entries) == 0 {
+					continue
 				}
 
-			}
+				_, err := s.sqsSvc.ChangeMessageVisibilityBatch(&sqs.ChangeMessageVisibilityBatchInput{
+					QueueUrl: queueURL,
+					Entries:  entries,
+				})
 
-			deleteMessageRequest := sqs.DeleteMessageBatchInput{
-				QueueUrl: queueURL,
-				Entries:  processedReceiptHandles,
+				if err != nil {
+					logrus.WithError(err).
+						Errorf("error extending visibility timeout %s", s.subscriberID)
+				}
 			}
+		}
+	}()
+
+	return extender
+}
+
+// handling sets the deadline of the message being handled, once it passes the
+// messages that did not succeed are no longer extended
+func (e *visibilityExtender) handling(deadline time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.deadline = deadline
+}
 
-			sqsSvc.DeleteMessageBatch(&deleteMessageRequest)
+// succeeded keeps extending the message i after a deadline passes
+func (e *visibilityExtender) succeeded(i int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
+	e.handled[i] = true
+}
+
+// release stops extending the message i so it becomes visible again
+func (e *visibilityExtender) release(i int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.entries, i)
+}
+
+// pending returns the entries still to extend, a handler past its deadline
+// holds up the rest of the batch so only the handled messages are kept
+func (e *visibilityExtender) pending() []*sqs.ChangeMessageVisibilityBatchRequestEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	expired := !e.deadline.IsZero() && time.Now().After(e.deadline)
+	entries := make([]*sqs.ChangeMessageVisibilityBatchRequestEntry, 0, len(e.entries))
+
+	for i, entry := range e.entries {
+		if expired && !e.handled[i] {
+			delete(e.entries, i)
+			continue
 		}
+
+		entries = append(entries, entry)
 	}
 
+	return entries
 }
 
-func convertQueueURLToARN(inputURL string) string {
-	var queueARN string
-	if strings.Contains(inputURL, "localhost") {
-		queueARN = strings.Replace(strings.Replace(strings.Replace(inputURL, "http://", "arn:aws:sqs:", -1), "localhost:4566", "us-west-2", -1), "/", ":", -1)
-	} else {
-		queueARN = strings.Replace(strings.Replace(strings.Replace(inputURL, "https://sqs.", "arn:aws:sqs:", -1), ".amazonaws.com/", ":", -1), "/", ":", -1)
+// stop stops extending and waits for the last call
+func (e *visibilityExtender) stop() {
+	close(e.done)
+	<-e.stopped
+}
+
+// timeout the handler timeout or a multiple of the visibility timeout
+func (s *MessageBrokerSubscriber) timeout() time.Duration {
+	if s.handlerTimeout > 0 {
+		return s.handlerTimeout
 	}
 
-	return queueARN
+	return s.visibilityTimeout * handlerTimeoutFactor
+}
+
+// visibilitySeconds sqs only accepts whole seconds, the timeout is rounded up
+func (s *MessageBrokerSubscriber) visibilitySeconds() int64 {
+	return int64((s.visibilityTimeout + time.Second - 1) / time.Second)
+}
+
+// chainHandler wraps the handler with the middlewares, panics are always
+// recovered so a handler cannot stop the consumer
+func chainHandler(handler Handler, middlewares []Middleware) Handler {
+	chain := make([]Middleware, 0, len(middlewares)+1)
+	chain = append(chain, HandlerRecovery())
+
+	return Chain(handler, append(chain, middlewares...)...)
 }
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type subscriberTestMessage struct {
+	Value string `json:"value"`
+}
+
+// fakeSQS returns the queued messages once and then long polls until cancelled
+type fakeSQS struct {
+	sqsiface.SQSAPI
+	mu                sync.Mutex
+	messages          []*sqs.Message
+	received          []*sqs.ReceiveMessageInput
+	deleted           []string
+	extended          int
+	extendedIDs       []string
+	releasedMalformed int
+}
+
+func (f *fakeSQS) ReceiveMessageWithContext(ctx aws.Context, input *sqs.ReceiveMessageInput, opts ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	f.received = append(f.received, input)
+	messages := f.messages
+	f.messages = nil
+	f.mu.Unlock()
+
+	if len(messages) > 0 {
+		return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+	}
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (f *fakeSQS) DeleteMessageBatch(input *sqs.DeleteMessageBatchInput) (*sqs.DeleteMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, entry := range input.Entries {
+		f.deleted = append(f.deleted, *entry.Id)
+	}
+
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibilityBatch(input *sqs.ChangeMessageVisibilityBatchInput) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if *input.Entries[0].VisibilityTimeout == 0 {
+		f.releasedMalformed += len(input.Entries)
+	} else {
+		f.extended++
+
+		for _, entry := range input.Entries {
+			f.extendedIDs = append(f.extendedIDs, *entry.Id)
+		}
+	}
+
+	return &sqs.ChangeMessageVisibilityBatchOutput{}, nil
+}
+
+type subscriberTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	sqs    *fakeSQS
+}
+
+func TestSubscriberTestSuite(t *testing.T) {
+	suite.Run(t, new(subscriberTestSuite))
+}
+
+func (s *subscriberTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *subscriberTestSuite) SetupTest() {
+	s.sqs = &fakeSQS{}
+}
+
+func (s *subscriberTestSuite) TestDeletesOnlyHandledMessages() {
+	s.sqs.messages = []*sqs.Message{
+		newSNSMessage("ok", `{\"value\":\"ok\"}`),
+		newSNSMessage("failed", `{\"value\":\"failed\"}`),
+		newSNSMessage("malformed", `not json`),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := 0
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			body := new(subscriberTestMessage)
+			if err := message.Decode(body); err != nil {
+				return err
+			}
+
+			handled++
+
+			if body.Value == "ok" {
+				return nil
+			}
+
+			// the last handled message of the batch stops the consumer
+			cancel()
+
+			return errors.New("handler failed")
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	s.assert.Equal(2, handled)
+	s.assert.Equal([]string{"ok"}, s.sqs.deleted)
+	s.assert.Equal(1, s.sqs.releasedMalformed)
+
+	s.assert.Equal(int64(10), *s.sqs.received[0].MaxNumberOfMessages)
+	s.assert.Equal(int64(20), *s.sqs.received[0].WaitTimeSeconds)
+}
+
+func (s *subscriberTestSuite) TestExtendsVisibilityOfSlowMessages() {
+	s.sqs.messages = []*sqs.Message{newSNSMessage("slow", `{\"value\":\"slow\"}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			return nil
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+	subscriber.visibilityTimeout = 20 * time.Millisecond
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	s.assert.GreaterOrEqual(s.sqs.extended, 1)
+	s.assert.Equal([]string{"slow"}, s.sqs.deleted)
+}
+
+func (s *subscriberTestSuite) TestHandlerContextHasDeadline() {
+	s.sqs.messages = []*sqs.Message{newSNSMessage("message", `{\"value\":\"message\"}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var deadline time.Time
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithVisibilityTimeout(time.Second),
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			deadline, _ = ctx.Deadline()
+			cancel()
+			return nil
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	s.assert.WithinDuration(time.Now().Add(10*time.Second), deadline, time.Second)
+}
+
+func (s *subscriberTestSuite) TestStopsExtendingFailedMessages() {
+	s.sqs.messages = []*sqs.Message{
+		newSNSMessage("failed", `{\"value\":\"failed\"}`),
+		newSNSMessage("slow", `{\"value\":\"slow\"}`),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			body := new(subscriberTestMessage)
+			if err := message.Decode(body); err != nil {
+				return err
+			}
+
+			if body.Value == "failed" {
+				return errors.New("handler failed")
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			return nil
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+	subscriber.visibilityTimeout = 20 * time.Millisecond
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	s.assert.GreaterOrEqual(s.sqs.extended, 1)
+	s.assert.NotContains(s.sqs.extendedIDs, "0")
+	s.assert.Contains(s.sqs.extendedIDs, "1")
+	s.assert.Equal([]string{"slow"}, s.sqs.deleted)
+}
+
+func (s *subscriberTestSuite) TestStopsExtendingAfterHandlerTimeout() {
+	s.sqs.messages = []*sqs.Message{
+		newSNSMessage("stuck", `{\"value\":\"stuck\"}`),
+		newSNSMessage("waiting", `{\"value\":\"waiting\"}`),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithHandlerTimeout(20*time.Millisecond),
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			// ignores the deadline and holds up the batch
+			time.Sleep(150 * time.Millisecond)
+			cancel()
+			return ctx.Err()
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+	subscriber.visibilityTimeout = 20 * time.Millisecond
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	// extended every 10ms only until the 20ms deadline passed
+	s.assert.GreaterOrEqual(s.sqs.extended, 1)
+	s.assert.Less(s.sqs.extended, 5)
+	s.assert.Empty(s.sqs.deleted)
+}
+
+func (s *subscriberTestSuite) TestWorkersStopOnShutdown() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriber := NewMessageBrokerSubscriber(WithWorkers(3))
+	subscriber.sqsSvc = s.sqs
+
+	done := make(chan struct{})
+	go func() {
+		subscriber.consume(ctx, aws.String("queue"))
+		close(done)
+	}()
+
+	s.assert.Eventually(func() bool {
+		s.sqs.mu.Lock()
+		defer s.sqs.mu.Unlock()
+
+		return len(s.sqs.received) == 3
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("consumer did not stop")
+	}
+}
+
+func (s *subscriberTestSuite) TestMetadataAndMiddlewares() {
+	mess := newSNSMessage("sqs-id", `{\"value\":\"ok\"}`)
+	mess.Body = aws.String(`{"Type":"Notification","MessageId":"sns-id","Message":"[1,2]",` +
+		`"MessageAttributes":{"correlation_id":{"Type":"String","Value":"abc"}}}`)
+	mess.Attributes = map[string]*string{"ApproximateReceiveCount": aws.String("2")}
+	s.sqs.messages = []*sqs.Message{mess}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	order := []string{}
+	var received *Message
+
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, message *Message) error {
+				order = append(order, name)
+				return next.Handle(ctx, message)
+			})
+		}
+	}
+
+	subscriber := NewMessageBrokerSubscriber(
+		WithMiddlewares(trace("outer"), trace("inner"), HandlerCorrelationID()),
+		WithHandler(HandlerFunc(func(ctx context.Context, message *Message) error {
+			defer cancel()
+
+			received = message
+			s.assert.Equal("abc", CorrelationIDFromContext(ctx))
+
+			panic("handler bug")
+		})),
+	)
+	subscriber.sqsSvc = s.sqs
+
+	subscriber.consume(ctx, aws.String("queue"))
+
+	s.assert.Equal([]string{"outer", "inner"}, order)
+	s.assert.Equal("sns-id", received.ID)
+	s.assert.Equal("[1,2]", string(received.Body))
+	s.assert.Equal(2, received.ReceiveCount)
+	s.assert.Equal("abc", received.CorrelationID)
+	s.assert.Empty(s.sqs.deleted)
+}
+
+func newSNSMessage(id, message string) *sqs.Message {
+	return &sqs.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String(id + "-receipt"),
+		Body:          aws.String(`{"Type":"Notification","Message":"` + message + `"}`),
+	}
+}
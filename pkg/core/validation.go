@@ -0,0 +1,86 @@
+package core
+
+import "fmt"
+
+const (
+	// RuleRequired violated when a mandatory field is empty
+	RuleRequired = "required"
+
+	// RuleMin violated when a value or length is lower than allowed
+	RuleMin = "min"
+
+	// RuleMax violated when a value or length is greater than allowed
+	RuleMax = "max"
+
+	// RuleUnique violated when a value is repeated
+	RuleUnique = "unique"
+
+	// RuleForbidden violated when a field is set but not allowed
+	RuleForbidden = "forbidden"
+)
+
+// Validation collects field violations so all of them can be returned at once
+type Validation struct {
+	violations []*Violation
+}
+
+// NewValidation ...
+func NewValidation() *Validation {
+	return &Validation{violations: []*Violation{}}
+}
+
+// Add registers a new violation for the given path
+func (v *Validation) Add(path, rule, message string) {
+	v.violations = append(v.violations, &Violation{
+		Path:    path,
+		Rule:    rule,
+		Message: message,
+	})
+}
+
+// Merge appends the violations of a validation error, prefixing their paths.
+// Errors that are not validation errors are ignored
+func (v *Validation) Merge(prefix string, err error) {
+	ierr, ok := err.(*Error)
+	if !ok || ierr.Key != ErrValidationFailed.Key {
+		return
+	}
+
+	for _, violation := range ierr.Violations {
+		v.Add(JoinPath(prefix, violation.Path), violation.Rule, violation.Message)
+	}
+}
+
+// Err returns a validation error with every collected violation, or nil
+func (v *Validation) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Key:        ErrValidationFailed.Key,
+		Violations: v.violations,
+	}
+}
+
+// JoinPath joins a parent and a child field path
+func JoinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+
+	if path == "" {
+		return prefix
+	}
+
+	if path[0] == '[' {
+		return prefix + path
+	}
+
+	return prefix + "." + path
+}
+
+// IndexPath returns the path of an element inside a list field
+func IndexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
@@ -0,0 +1,232 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// AuctionStatus ...
+type AuctionStatus string
+
+const (
+	// AuctionOpen accepting bids until the deadline
+	AuctionOpen AuctionStatus = "Open"
+
+	// AuctionClosing a winner is being settled
+	AuctionClosing AuctionStatus = "Closing"
+
+	// AuctionSettled the winning bid was traded
+	AuctionSettled AuctionStatus = "Settled"
+
+	// AuctionExpired the deadline passed without bids
+	AuctionExpired AuctionStatus = "Expired"
+
+	// AuctionError the winning bid could not be traded
+	AuctionError AuctionStatus = "Error"
+)
+
+// BidStatus ...
+type BidStatus string
+
+const (
+	// BidActive ...
+	BidActive BidStatus = "Active"
+
+	// BidWon ...
+	BidWon BidStatus = "Won"
+
+	// BidLost ...
+	BidLost BidStatus = "Lost"
+)
+
+// Bid items and currency offered by a user for the auctioned items
+type Bid struct {
+	ID        string    `bson:"id"`
+	BidderID  string    `bson:"bidder_id"`
+	Status    BidStatus `bson:"status"`
+	Items     []*Item   `bson:"items"`
+	Currency  int64     `bson:"currency"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// Auction ...
+type Auction struct {
+	ID        string        `bson:"_id"`
+	OwnerID   string        `bson:"owner_id"`
+	Status    AuctionStatus `bson:"status"`
+	Items     []*Item       `bson:"items"`
+	Bids      []*Bid        `bson:"bids"`
+	WinnerID  string        `bson:"winner_id"`
+	TradeID   string        `bson:"trade_id"`
+	EndsAt    time.Time     `bson:"ends_at"`
+	CreatedAt time.Time     `bson:"created_at"`
+	UpdatedAt *time.Time    `bson:"updated_at"`
+}
+
+// GetAuctions ...
+type GetAuctions struct {
+	Token    *string
+	PageSize int64
+}
+
+// ResultAuctions ...
+type ResultAuctions struct {
+	Auctions []*Auction
+	Token    string
+}
+
+// AuctionRepository ...
+type AuctionRepository interface {
+	Insert(ctx context.Context, auction *Auction) error
+	Update(ctx context.Context, auction *Auction) error
+	Get(ctx context.Context, req *GetAuctions) (*ResultAuctions, error)
+	GetByID(ctx context.Context, id string) (*Auction, error)
+
+	// GetExpired returns open auctions whose deadline is before now and closing
+	// auctions last updated before staleBefore
+	GetExpired(ctx context.Context, now, staleBefore time.Time, limit int64) ([]*Auction, error)
+
+	// AddBid atomically appends the bid while the auction is open and before its
+	// deadline, core.ErrNotFound is returned if nothing matched
+	AddBid(ctx context.Context, id string, bid *Bid) error
+
+	// UpdateStatus persists the auction status only if the stored one still equals from
+	UpdateStatus(ctx context.Context, auction *Auction, from AuctionStatus) (bool, error)
+
+	// ClaimStale persists the updated at of a closing auction only if it was
+	// last updated before staleBefore, so a single process settles it again
+	ClaimStale(ctx context.Context, auction *Auction, staleBefore time.Time) (bool, error)
+}
+
+// AuctionService ...
+type AuctionService interface {
+	Create(ctx context.Context, userID, correlationID string, req *CreateAuctionRequest) (*CreateAuctionResponse, error)
+	Bid(ctx context.Context, userID, correlationID, id string, req *CreateBidRequest) (*CreateBidResponse, error)
+	AcceptBid(ctx context.Context, userID, correlationID, id, bidID string) error
+	Get(ctx context.Context, req *GetAuctionsRequest) (*GetAuctionsResponse, error)
+	GetByID(ctx context.Context, id string) (*GetAuctionResponse, error)
+
+	// CloseExpired settles every auction that reached its deadline
+	CloseExpired(ctx context.Context) error
+}
+
+// NewAuction ...
+func NewAuction(id, ownerID string, items []*Item, endsAt time.Time, maxDuration time.Duration) (*Auction, error) {
+
+	validation := core.NewValidation()
+	now := time.Now()
+
+	if id == "" {
+		validation.Add("id", core.RuleRequired, "id is required")
+	}
+
+	if ownerID == "" {
+		validation.Add("owner_id", core.RuleRequired, "owner id is required")
+	}
+
+	if len(items) < 1 {
+		validation.Add("items", core.RuleMin, "at least one item is required")
+	}
+
+	if !endsAt.After(now) {
+		validation.Add("ends_at", core.RuleMin, "deadline must be in the future")
+	}
+
+	if maxDuration > 0 && endsAt.Sub(now) > maxDuration {
+		validation.Add("ends_at", core.RuleMax, "deadline is too far in the future")
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Auction{
+		ID:        id,
+		OwnerID:   ownerID,
+		Status:    AuctionOpen,
+		Items:     items,
+		Bids:      []*Bid{},
+		EndsAt:    endsAt,
+		CreatedAt: now,
+	}, nil
+}
+
+// NewBid ...
+func NewBid(id, bidderID string, items []*Item, currency int64) (*Bid, error) {
+
+	validation := core.NewValidation()
+
+	if id == "" {
+		validation.Add("id", core.RuleRequired, "id is required")
+	}
+
+	if bidderID == "" {
+		validation.Add("bidder_id", core.RuleRequired, "bidder id is required")
+	}
+
+	if currency < 0 {
+		validation.Add("currency", core.RuleMin, "currency can't be negative")
+	}
+
+	if len(items) < 1 && currency < 1 {
+		validation.Add("items", core.RuleMin, "at least one item or currency is required")
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Bid{
+		ID:        id,
+		BidderID:  bidderID,
+		Status:    BidActive,
+		Items:     items,
+		Currency:  currency,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Bid returns the bid with the given id or nil
+func (auction *Auction) Bid(id string) *Bid {
+	for _, bid := range auction.Bids {
+		if bid.ID == id {
+			return bid
+		}
+	}
+
+	return nil
+}
+
+// HighestBid returns the bid with more currency, the earliest one wins ties
+func (auction *Auction) HighestBid() *Bid {
+	var highest *Bid
+
+	for _, bid := range auction.Bids {
+		if highest == nil || bid.Currency > highest.Currency {
+			highest = bid
+		}
+	}
+
+	return highest
+}
+
+// WonBid returns the bid chosen by a settlement that did not finish or nil
+func (auction *Auction) WonBid() *Bid {
+	for _, bid := range auction.Bids {
+		if bid.Status == BidWon {
+			return bid
+		}
+	}
+
+	return nil
+}
+
+// UpdateStatus ...
+func (auction *Auction) UpdateStatus(status AuctionStatus) {
+	auction.Status = status
+
+	now := time.Now()
+	auction.UpdatedAt = &now
+}
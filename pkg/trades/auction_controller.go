@@ -0,0 +1,124 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// AuctionController ...
+type AuctionController struct {
+	authenticate *core.Authenticate
+	service      AuctionService
+}
+
+// NewAuctionController ...
+func NewAuctionController(authenticate *core.Authenticate, service AuctionService) AuctionController {
+	return AuctionController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *AuctionController) RegisterRoutes(r *gin.RouterGroup) {
+	auctions := r.Group("/trades/auctions")
+	{
+		auctions.Use(
+			c.authenticate.Middleware(),
+		)
+
+		auctions.POST("", c.post)
+		auctions.POST(":id/bids", c.bid)
+		auctions.POST(":id/bids/:bid_id/accept", c.acceptBid)
+		auctions.GET("", c.get)
+		auctions.GET(":id", c.getByID)
+	}
+}
+
+func (c *AuctionController) post(ctx *gin.Context) {
+	req := new(CreateAuctionRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Create(ctx, userID, correlationID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *AuctionController) bid(ctx *gin.Context) {
+	req := new(CreateBidRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Bid(ctx, userID, correlationID, id, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *AuctionController) acceptBid(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+	bidID := ctx.Param("bid_id")
+
+	if err := c.service.AcceptBid(ctx, userID, correlationID, id, bidID); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *AuctionController) get(ctx *gin.Context) {
+	req := new(GetAuctionsRequest)
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *AuctionController) getByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := c.service.GetByID(ctx, id)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
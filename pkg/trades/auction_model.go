@@ -0,0 +1,114 @@
+package trades
+
+import "time"
+
+// BidModel ...
+type BidModel struct {
+	ID        string       `json:"id"`
+	BidderID  string       `json:"bidder_id"`
+	Status    string       `json:"status"`
+	Items     []*ItemModel `json:"items"`
+	Currency  int64        `json:"currency"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// AuctionModel ...
+type AuctionModel struct {
+	ID        string       `json:"id"`
+	OwnerID   string       `json:"owner_id"`
+	Status    string       `json:"status"`
+	Items     []*ItemModel `json:"items"`
+	Bids      []*BidModel  `json:"bids"`
+	WinnerID  string       `json:"winner_id,omitempty"`
+	TradeID   string       `json:"trade_id,omitempty"`
+	EndsAt    time.Time    `json:"ends_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt *time.Time   `json:"updated_at"`
+}
+
+// CreateAuctionRequest ...
+type CreateAuctionRequest struct {
+	Items  []*ItemModel `json:"items"`
+	EndsAt time.Time    `json:"ends_at"`
+}
+
+// CreateAuctionResponse ...
+type CreateAuctionResponse struct {
+	ID string `json:"id"`
+}
+
+// CreateBidRequest ...
+type CreateBidRequest struct {
+	Items    []*ItemModel `json:"items"`
+	Currency int64        `json:"currency"`
+}
+
+// CreateBidResponse ...
+type CreateBidResponse struct {
+	ID string `json:"id"`
+}
+
+// GetAuctionsRequest ...
+type GetAuctionsRequest struct {
+	Token    *string `form:"token"`
+	PageSize int64   `form:"page_size"`
+}
+
+// GetAuctionsResponse ...
+type GetAuctionsResponse struct {
+	Auctions []*AuctionModel `json:"auctions"`
+	Token    string          `json:"token"`
+}
+
+// GetAuctionResponse ...
+type GetAuctionResponse struct {
+	Auction *AuctionModel `json:"auction"`
+}
+
+// ParseBid ...
+func ParseBid(bid *Bid) *BidModel {
+	return &BidModel{
+		ID:        bid.ID,
+		BidderID:  bid.BidderID,
+		Status:    string(bid.Status),
+		Items:     ParseItemSlice(bid.Items),
+		Currency:  bid.Currency,
+		CreatedAt: bid.CreatedAt,
+	}
+}
+
+// ParseAuction ...
+func ParseAuction(auction *Auction) *AuctionModel {
+	bids := make([]*BidModel, len(auction.Bids))
+
+	for i, bid := range auction.Bids {
+		bids[i] = ParseBid(bid)
+	}
+
+	return &AuctionModel{
+		ID:        auction.ID,
+		OwnerID:   auction.OwnerID,
+		Status:    string(auction.Status),
+		Items:     ParseItemSlice(auction.Items),
+		Bids:      bids,
+		WinnerID:  auction.WinnerID,
+		TradeID:   auction.TradeID,
+		EndsAt:    auction.EndsAt,
+		CreatedAt: auction.CreatedAt,
+		UpdatedAt: auction.UpdatedAt,
+	}
+}
+
+// ParseGetAuctionsResponse ...
+func ParseGetAuctionsResponse(res *ResultAuctions) *GetAuctionsResponse {
+	auctions := make([]*AuctionModel, len(res.Auctions))
+
+	for i, auction := range res.Auctions {
+		auctions[i] = ParseAuction(auction)
+	}
+
+	return &GetAuctionsResponse{
+		Token:    res.Token,
+		Auctions: auctions,
+	}
+}
@@ -0,0 +1,499 @@
+package trades
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type auctionService struct {
+	repository       AuctionRepository
+	tradeService     Service
+	inventoryService inventory.Service
+	settings         *core.Auctions
+	transitions      TransitionHandler
+}
+
+// AuctionServiceOption ...
+type AuctionServiceOption func(*auctionService)
+
+// NewAuctionService ...
+func NewAuctionService(
+	repository AuctionRepository,
+	tradeService Service,
+	inventoryService inventory.Service,
+	settings *core.Auctions,
+	opts ...AuctionServiceOption,
+) AuctionService {
+
+	// copied so the defaults are not written into the shared settings
+	conf := core.Auctions{}
+	if settings != nil {
+		conf = *settings
+	}
+
+	if conf.CloseBatchSize < 1 {
+		conf.CloseBatchSize = 50
+	}
+
+	if conf.SettleTimeout <= 0 {
+		conf.SettleTimeout = 10 * time.Minute
+	}
+
+	s := &auctionService{
+		repository:       repository,
+		tradeService:     tradeService,
+		inventoryService: inventoryService,
+		settings:         &conf,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithAuctionTransitionHandler - default nobody is told when auctions expire
+func WithAuctionTransitionHandler(handler TransitionHandler) AuctionServiceOption {
+	return func(s *auctionService) {
+		s.transitions = handler
+	}
+}
+
+func (s *auctionService) Create(
+	ctx context.Context,
+	userID, correlationID string,
+	req *CreateAuctionRequest,
+) (*CreateAuctionResponse, error) {
+
+	fields := logrus.Fields{
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	items, err := ToDomain("items", req.Items)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error parsing auction items")
+		return nil, err
+	}
+
+	auction, err := NewAuction(uuid.NewString(), userID, items, req.EndsAt, s.settings.MaxDuration)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error creating new auction")
+		return nil, err
+	}
+
+	fields["auction_id"] = auction.ID
+
+	// locked under the auction id so the winning bid is traded with the same lock
+	lockItemsReq := &inventory.LockItemsRequest{
+		LockedBy:     auction.ID,
+		OwnerID:      auction.OwnerID,
+		OfferedItems: toItemsToLock(auction.Items),
+		WantedItems:  []*inventory.ItemToLock{},
+	}
+
+	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error locking auction items")
+		return nil, core.ErrLockFailed
+	}
+
+	if err := s.repository.Insert(ctx, auction); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error inserting auction")
+
+		s.unlockAuction(ctx, auction, fields)
+
+		return nil, err
+	}
+
+	logrus.WithFields(fields).Info("new auction created")
+
+	return &CreateAuctionResponse{ID: auction.ID}, nil
+}
+
+func (s *auctionService) Bid(
+	ctx context.Context,
+	userID, correlationID, id string,
+	req *CreateBidRequest,
+) (*CreateBidResponse, error) {
+
+	fields := logrus.Fields{
+		"auction_id":     id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	auction, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting auction")
+		return nil, err
+	}
+
+	if auction.Status != AuctionOpen || !time.Now().Before(auction.EndsAt) {
+		logrus.WithError(core.ErrAuctionClosed).WithFields(fields).Error("tried to bid on a closed auction")
+		return nil, core.ErrAuctionClosed
+	}
+
+	if auction.OwnerID == userID {
+		logrus.WithError(core.ErrAuctionSelfBid).WithFields(fields).Error("tried to bid on own auction")
+		return nil, core.ErrAuctionSelfBid
+	}
+
+	items, err := ToDomain("items", req.Items)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error parsing bid items")
+		return nil, err
+	}
+
+	bid, err := NewBid(uuid.NewString(), userID, items, req.Currency)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error creating new bid")
+		return nil, err
+	}
+
+	fields["bid_id"] = bid.ID
+
+	// every bid adds to the auction lock, each bidder is released on its own
+	lockItemsReq := &inventory.LockItemsRequest{
+		LockedBy:        auction.ID,
+		OwnerID:         bid.BidderID,
+		OfferedItems:    toItemsToLock(bid.Items),
+		WantedItems:     []*inventory.ItemToLock{},
+		OfferedCurrency: bid.Currency,
+	}
+
+	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error locking bid items")
+		return nil, core.ErrLockFailed
+	}
+
+	if err := s.repository.AddBid(ctx, id, bid); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error adding bid")
+
+		s.unlockBid(ctx, auction, bid, fields)
+
+		if err == core.ErrNotFound {
+			return nil, core.ErrAuctionClosed
+		}
+
+		return nil, err
+	}
+
+	logrus.WithFields(fields).Info("new bid placed")
+
+	return &CreateBidResponse{ID: bid.ID}, nil
+}
+
+func (s *auctionService) AcceptBid(ctx context.Context, userID, correlationID, id, bidID string) error {
+
+	fields := logrus.Fields{
+		"auction_id":     id,
+		"bid_id":         bidID,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	auction, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting auction")
+		return err
+	}
+
+	if auction.OwnerID != userID {
+		logrus.WithError(core.ErrForbidden).WithFields(fields).Error("only the owner can accept a bid")
+		return core.ErrForbidden
+	}
+
+	if auction.Status != AuctionOpen {
+		logrus.WithError(core.ErrAuctionClosed).WithFields(fields).Error("tried to accept a bid of a closed auction")
+		return core.ErrAuctionClosed
+	}
+
+	if auction.Bid(bidID) == nil {
+		logrus.WithError(core.ErrNotFound).WithFields(fields).Error("bid not found")
+		return core.ErrNotFound
+	}
+
+	auction, err = s.close(ctx, auction, fields)
+	if err != nil {
+		return err
+	}
+
+	if auction == nil {
+		return core.ErrAuctionClosed
+	}
+
+	return s.settle(ctx, auction, auction.Bid(bidID), correlationID, fields)
+}
+
+func (s *auctionService) Get(ctx context.Context, req *GetAuctionsRequest) (*GetAuctionsResponse, error) {
+
+	res, err := s.repository.Get(ctx, &GetAuctions{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	})
+
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithField("token", req.Token).
+			Error("error getting auctions")
+		return nil, err
+	}
+
+	return ParseGetAuctionsResponse(res), nil
+}
+
+func (s *auctionService) GetByID(ctx context.Context, id string) (*GetAuctionResponse, error) {
+
+	auction, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithField("auction_id", id).
+			Error("error getting auction")
+		return nil, err
+	}
+
+	return &GetAuctionResponse{Auction: ParseAuction(auction)}, nil
+}
+
+func (s *auctionService) CloseExpired(ctx context.Context) error {
+
+	for {
+		now := time.Now()
+
+		auctions, err := s.repository.GetExpired(ctx, now, now.Add(-s.settings.SettleTimeout), s.settings.CloseBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("error getting expired auctions")
+			return err
+		}
+
+		for _, auction := range auctions {
+			correlationID := uuid.NewString()
+
+			fields := logrus.Fields{
+				"auction_id":     auction.ID,
+				"correlation_id": correlationID,
+			}
+
+			auction, err := s.close(ctx, auction, fields)
+			if err != nil {
+				return err
+			}
+
+			// closed by the owner accepting a bid in the meantime
+			if auction == nil {
+				continue
+			}
+
+			// a settlement retried after a crash keeps the winner it chose
+			winner := auction.WonBid()
+			if winner == nil {
+				winner = auction.HighestBid()
+			}
+
+			if err := s.settle(ctx, auction, winner, correlationID, fields); err != nil {
+				logrus.WithError(err).WithFields(fields).Error("error settling auction")
+			}
+		}
+
+		if int64(len(auctions)) < s.settings.CloseBatchSize {
+			return nil
+		}
+	}
+}
+
+// close moves the auction out of open, or claims a closing auction whose
+// settlement stalled, and returns its final state. nil is returned when
+// another process closed or claimed it first
+func (s *auctionService) close(ctx context.Context, auction *Auction, fields logrus.Fields) (*Auction, error) {
+
+	from := auction.Status
+	auction.UpdateStatus(AuctionClosing)
+
+	var updated bool
+	var err error
+
+	if from == AuctionClosing {
+		updated, err = s.repository.ClaimStale(ctx, auction, auction.UpdatedAt.Add(-s.settings.SettleTimeout))
+	} else {
+		updated, err = s.repository.UpdateStatus(ctx, auction, AuctionOpen)
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error closing auction")
+		return nil, err
+	}
+
+	if !updated {
+		logrus.WithFields(fields).Info("auction was already closed")
+		return nil, nil
+	}
+
+	logrus.WithFields(fields).Info("auction status set to closing")
+
+	// reloaded so bids placed before closing are settled as well
+	auction, err = s.repository.GetByID(ctx, auction.ID)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting auction")
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// settle converts the winning bid into a trade offer holding the auction
+// lock and only then releases the losing bids. Failures other than the items
+// trade leave the auction closing so the close job settles it again
+func (s *auctionService) settle(ctx context.Context, auction *Auction, winner *Bid, correlationID string, fields logrus.Fields) error {
+
+	if winner == nil {
+		return s.expire(ctx, auction, fields)
+	}
+
+	fields["bid_id"] = winner.ID
+
+	trade, err := NewTradeOffer(
+		auction.ID,
+		auction.OwnerID,
+		winner.BidderID,
+		auction.Items,
+		winner.Items,
+		0,
+		winner.Currency,
+	)
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error creating trade offer of winning bid")
+		return err
+	}
+
+	winner.Status = BidWon
+	auction.WinnerID = winner.BidderID
+	auction.TradeID = trade.ID
+
+	// the winner is persisted first so a retried settlement trades the same bid
+	if err := s.repository.Update(ctx, auction); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating auction")
+		return err
+	}
+
+	err = s.tradeService.TradeLocked(ctx, correlationID, trade)
+
+	if errors.Is(err, core.ErrItemsTradeFailed) {
+		logrus.WithError(err).WithFields(fields).Error("error trading winning bid")
+
+		s.loseBids(auction, winner)
+		s.unlockAuction(ctx, auction, fields)
+
+		auction.UpdateStatus(AuctionError)
+
+		if err := s.repository.Update(ctx, auction); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating auction")
+			return err
+		}
+
+		logrus.WithFields(fields).Info("auction status set to error")
+
+		return err
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error trading winning bid")
+		return err
+	}
+
+	for _, bid := range s.loseBids(auction, winner) {
+		s.unlockBid(ctx, auction, bid, fields)
+	}
+
+	auction.UpdateStatus(AuctionSettled)
+
+	if err := s.repository.Update(ctx, auction); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating auction")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("auction status set to settled")
+
+	return nil
+}
+
+// expire releases the auction lock of an auction that ended without bids
+func (s *auctionService) expire(ctx context.Context, auction *Auction, fields logrus.Fields) error {
+
+	s.loseBids(auction, nil)
+	s.unlockAuction(ctx, auction, fields)
+
+	auction.UpdateStatus(AuctionExpired)
+
+	if err := s.repository.Update(ctx, auction); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating auction")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("auction status set to expired")
+
+	if s.transitions != nil {
+		transition := &Transition{
+			TradeID:    auction.ID,
+			OwnerID:    auction.OwnerID,
+			Outcome:    OutcomeExpired,
+			OccurredAt: time.Now(),
+		}
+
+		if err := s.transitions.HandleTransition(ctx, transition); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error handling auction transition")
+		}
+	}
+
+	return nil
+}
+
+// loseBids marks every bid but the winner as lost and returns them
+func (s *auctionService) loseBids(auction *Auction, winner *Bid) []*Bid {
+	lost := []*Bid{}
+
+	for _, bid := range auction.Bids {
+		if winner != nil && bid.ID == winner.ID {
+			continue
+		}
+
+		bid.Status = BidLost
+		lost = append(lost, bid)
+	}
+
+	return lost
+}
+
+// unlockBid releases only the items and currency of the bid from the auction lock
+func (s *auctionService) unlockBid(ctx context.Context, auction *Auction, bid *Bid, fields logrus.Fields) {
+	req := &inventory.UnlockItemsRequest{
+		LockedBy: auction.ID,
+		OwnerID:  bid.BidderID,
+		Items:    toItemsToLock(bid.Items),
+		Currency: bid.Currency,
+	}
+
+	if err := s.inventoryService.UnlockItems(ctx, req); err != nil {
+		logrus.
+			WithError(err).
+			WithFields(fields).
+			WithField("bid_id", bid.ID).
+			Error("error unlocking bid items")
+	}
+}
+
+// unlockAuction releases the auctioned items and every bid
+func (s *auctionService) unlockAuction(ctx context.Context, auction *Auction, fields logrus.Fields) {
+	if err := s.inventoryService.UnlockItems(ctx, &inventory.UnlockItemsRequest{LockedBy: auction.ID}); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error unlocking auction items")
+	}
+}
@@ -0,0 +1,300 @@
+package trades_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type auctionServiceTestSuite struct {
+	suite.Suite
+	assert           *assert.Assertions
+	ctx              context.Context
+	repository       *mock.AuctionRepositoryMock
+	tradeService     *mock.TradeServiceMock
+	inventoryService *mock.InventoryServiceMock
+	service          trades.AuctionService
+}
+
+func TestAuctionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(auctionServiceTestSuite))
+}
+
+func (s *auctionServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *auctionServiceTestSuite) SetupTest() {
+	s.repository = mock.NewAuctionRepository().(*mock.AuctionRepositoryMock)
+	s.tradeService = mock.NewTradeService().(*mock.TradeServiceMock)
+	s.inventoryService = mock.NewInventoryService().(*mock.InventoryServiceMock)
+	s.service = trades.NewAuctionService(s.repository, s.tradeService, s.inventoryService, &core.Auctions{CloseBatchSize: 10})
+}
+
+func (s *auctionServiceTestSuite) TestCreate() {
+	req := &trades.CreateAuctionRequest{
+		Items:  []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		EndsAt: time.Now().Add(time.Hour),
+	}
+
+	s.repository.On("Insert").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Create(s.ctx, uuid.NewString(), uuid.NewString(), req)
+
+	s.assert.NoError(err)
+	s.assert.NotEmpty(res.ID)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+}
+
+func (s *auctionServiceTestSuite) TestCreateLockFailed() {
+	req := &trades.CreateAuctionRequest{
+		Items:  []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		EndsAt: time.Now().Add(time.Hour),
+	}
+
+	s.inventoryService.On("LockItems").Return(errors.New("not enough items"))
+
+	res, err := s.service.Create(s.ctx, uuid.NewString(), uuid.NewString(), req)
+
+	s.assert.ErrorIs(err, core.ErrLockFailed)
+	s.assert.Nil(res)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *auctionServiceTestSuite) TestBid() {
+	auction := newOpenAuction()
+	userID := uuid.NewString()
+
+	req := &trades.CreateBidRequest{
+		Items:    []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		Currency: 10,
+	}
+
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("AddBid", auction.ID).Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Bid(s.ctx, userID, uuid.NewString(), auction.ID, req)
+
+	s.assert.NoError(err)
+	s.assert.NotEmpty(res.ID)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "AddBid", 1)
+}
+
+func (s *auctionServiceTestSuite) TestBidOwnAuction() {
+	auction := newOpenAuction()
+
+	req := &trades.CreateBidRequest{Currency: 10}
+
+	s.repository.On("GetByID", auction.ID).Return(auction)
+
+	res, err := s.service.Bid(s.ctx, auction.OwnerID, uuid.NewString(), auction.ID, req)
+
+	s.assert.ErrorIs(err, core.ErrAuctionSelfBid)
+	s.assert.Nil(res)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *auctionServiceTestSuite) TestBidClosedWhileLocking() {
+	auction := newOpenAuction()
+
+	req := &trades.CreateBidRequest{Currency: 10}
+
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("AddBid", auction.ID).Return(core.ErrNotFound)
+	s.inventoryService.On("LockItems").Return(nil)
+	s.inventoryService.On("UnlockItems", auction.ID).Return(nil)
+
+	res, err := s.service.Bid(s.ctx, uuid.NewString(), uuid.NewString(), auction.ID, req)
+
+	s.assert.ErrorIs(err, core.ErrAuctionClosed)
+	s.assert.Nil(res)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *auctionServiceTestSuite) TestCloseExpiredSettlesHighestBid() {
+	auction := newOpenAuction()
+	auction.EndsAt = time.Now().Add(-time.Minute)
+
+	low := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 5}
+	high := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 50}
+	auction.Bids = []*trades.Bid{low, high}
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("UpdateStatus", trades.AuctionOpen).Return(true, nil)
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("UnlockItems", auction.ID).Return(nil)
+	s.tradeService.On("TradeLocked", auction.ID).Return(nil)
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.AuctionSettled, auction.Status)
+	s.assert.Equal(high.BidderID, auction.WinnerID)
+	s.assert.Equal(auction.ID, auction.TradeID)
+	s.assert.Equal(trades.BidWon, high.Status)
+	s.assert.Equal(trades.BidLost, low.Status)
+
+	// only the losing bid is released, the winner is traded with the auction lock
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+	s.tradeService.AssertNumberOfCalls(s.T(), "TradeLocked", 1)
+	s.tradeService.AssertNumberOfCalls(s.T(), "Create", 0)
+}
+
+func (s *auctionServiceTestSuite) TestSettleFailureKeepsLosingBidsLocked() {
+	auction := newOpenAuction()
+	auction.EndsAt = time.Now().Add(-time.Minute)
+
+	low := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 5}
+	high := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 50}
+	auction.Bids = []*trades.Bid{low, high}
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("UpdateStatus", trades.AuctionOpen).Return(true, nil)
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("Update").Return(nil)
+	s.tradeService.On("TradeLocked", auction.ID).Return(errors.New("connection reset"))
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.AuctionClosing, auction.Status)
+	s.assert.Equal(trades.BidWon, high.Status)
+	s.assert.Equal(trades.BidActive, low.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 0)
+}
+
+func (s *auctionServiceTestSuite) TestSettleItemsTradeFailedReleasesAuction() {
+	auction := newOpenAuction()
+	auction.EndsAt = time.Now().Add(-time.Minute)
+
+	low := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 5}
+	high := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 50}
+	auction.Bids = []*trades.Bid{low, high}
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("UpdateStatus", trades.AuctionOpen).Return(true, nil)
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("UnlockItems", auction.ID).Return(nil)
+	s.tradeService.On("TradeLocked", auction.ID).Return(core.ErrItemsTradeFailed)
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.AuctionError, auction.Status)
+	s.assert.Equal(trades.BidLost, low.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *auctionServiceTestSuite) TestCloseExpiredRetriesStaleClosing() {
+	auction := newOpenAuction()
+	auction.Status = trades.AuctionClosing
+	auction.EndsAt = time.Now().Add(-time.Hour)
+
+	// the winner chosen before the crash is kept even if it is not the highest
+	won := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidWon, Currency: 5}
+	high := &trades.Bid{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidActive, Currency: 50}
+	auction.Bids = []*trades.Bid{won, high}
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("ClaimStale", auction.ID).Return(true, nil)
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("UnlockItems", auction.ID).Return(nil)
+	s.tradeService.On("TradeLocked", auction.ID).Return(nil)
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.AuctionSettled, auction.Status)
+	s.assert.Equal(won.BidderID, auction.WinnerID)
+	s.assert.Equal(trades.BidLost, high.Status)
+
+	s.repository.AssertNumberOfCalls(s.T(), "UpdateStatus", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *auctionServiceTestSuite) TestCloseExpiredSkipsClaimedClosing() {
+	auction := newOpenAuction()
+	auction.Status = trades.AuctionClosing
+	auction.EndsAt = time.Now().Add(-time.Hour)
+	auction.Bids = []*trades.Bid{{ID: uuid.NewString(), BidderID: uuid.NewString(), Status: trades.BidWon, Currency: 5}}
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("ClaimStale", auction.ID).Return(false, nil)
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+
+	s.repository.AssertNumberOfCalls(s.T(), "GetByID", 0)
+	s.tradeService.AssertNumberOfCalls(s.T(), "TradeLocked", 0)
+}
+
+func (s *auctionServiceTestSuite) TestCloseExpiredWithoutBids() {
+	auction := newOpenAuction()
+	auction.EndsAt = time.Now().Add(-time.Minute)
+
+	s.repository.On("GetExpired").Return([]*trades.Auction{auction})
+	s.repository.On("UpdateStatus", trades.AuctionOpen).Return(true, nil)
+	s.repository.On("GetByID", auction.ID).Return(auction)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("UnlockItems", auction.ID).Return(nil)
+
+	err := s.service.CloseExpired(s.ctx)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.AuctionExpired, auction.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+	s.tradeService.AssertNumberOfCalls(s.T(), "TradeLocked", 0)
+}
+
+func (s *auctionServiceTestSuite) TestAcceptBidNotOwner() {
+	auction := newOpenAuction()
+
+	s.repository.On("GetByID", auction.ID).Return(auction)
+
+	err := s.service.AcceptBid(s.ctx, uuid.NewString(), uuid.NewString(), auction.ID, uuid.NewString())
+
+	s.assert.ErrorIs(err, core.ErrForbidden)
+
+	s.repository.AssertNumberOfCalls(s.T(), "UpdateStatus", 0)
+}
+
+func newOpenAuction() *trades.Auction {
+	return &trades.Auction{
+		ID:      uuid.NewString(),
+		OwnerID: uuid.NewString(),
+		Status:  trades.AuctionOpen,
+		Items: []*trades.Item{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+		Bids:   []*trades.Bid{},
+		EndsAt: time.Now().Add(time.Hour),
+	}
+}
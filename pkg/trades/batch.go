@@ -0,0 +1,10 @@
+package trades
+
+import "context"
+
+// BatchService runs Service operations over many trades at once
+type BatchService interface {
+	Get(ctx context.Context, userID string, req *BatchRequest) (*BatchResponse, error)
+	Cancel(ctx context.Context, userID, correlationID string, req *BatchRequest) (*BatchResponse, error)
+	Decline(ctx context.Context, userID, correlationID string, req *BatchRequest) (*BatchResponse, error)
+}
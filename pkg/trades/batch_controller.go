@@ -0,0 +1,95 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// BatchController ...
+type BatchController struct {
+	authenticate *core.Authenticate
+	service      BatchService
+}
+
+// NewBatchController ...
+func NewBatchController(authenticate *core.Authenticate, service BatchService) BatchController {
+	return BatchController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *BatchController) RegisterRoutes(r *gin.RouterGroup) {
+	batch := r.Group("/trades/batch")
+	{
+		batch.Use(
+			c.authenticate.Middleware(),
+		)
+
+		batch.POST("get", c.get)
+		batch.POST("cancel", c.cancel)
+		batch.POST("decline", c.decline)
+	}
+}
+
+func (c *BatchController) get(ctx *gin.Context) {
+	req := new(BatchRequest)
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, userID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *BatchController) cancel(ctx *gin.Context) {
+	req := new(BatchRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Cancel(ctx, userID, correlationID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *BatchController) decline(ctx *gin.Context) {
+	req := new(BatchRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Decline(ctx, userID, correlationID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
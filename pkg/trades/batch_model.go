@@ -0,0 +1,19 @@
+package trades
+
+// BatchRequest ...
+type BatchRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchResult outcome of a single trade, error holds the same key the single
+// trade endpoints would return
+type BatchResult struct {
+	ID    string           `json:"id"`
+	Error string           `json:"error,omitempty"`
+	Trade *TradeOfferModel `json:"trade,omitempty"`
+}
+
+// BatchResponse results are in the same order as the requested ids
+type BatchResponse struct {
+	Results []*BatchResult `json:"results"`
+}
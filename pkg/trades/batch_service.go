@@ -0,0 +1,124 @@
+package trades
+
+import (
+	"context"
+	"sync"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+)
+
+type batchService struct {
+	service     Service
+	maxIDs      int
+	concurrency int
+}
+
+// NewBatchService ...
+func NewBatchService(service Service, settings *core.Batch) BatchService {
+
+	s := &batchService{
+		service:     service,
+		maxIDs:      100,
+		concurrency: 8,
+	}
+
+	if settings != nil && settings.MaxIDs > 0 {
+		s.maxIDs = settings.MaxIDs
+	}
+
+	if settings != nil && settings.Concurrency > 0 {
+		s.concurrency = settings.Concurrency
+	}
+
+	return s
+}
+
+func (s *batchService) Get(ctx context.Context, userID string, req *BatchRequest) (*BatchResponse, error) {
+	return s.run(req, func(result *BatchResult) error {
+		res, err := s.service.GetByID(ctx, userID, result.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Trade = res.Trade
+
+		return nil
+	})
+}
+
+func (s *batchService) Cancel(ctx context.Context, userID, correlationID string, req *BatchRequest) (*BatchResponse, error) {
+	return s.run(req, func(result *BatchResult) error {
+		return s.service.Cancel(ctx, userID, correlationID, result.ID)
+	})
+}
+
+func (s *batchService) Decline(ctx context.Context, userID, correlationID string, req *BatchRequest) (*BatchResponse, error) {
+	return s.run(req, func(result *BatchResult) error {
+		return s.service.Decline(ctx, userID, correlationID, result.ID)
+	})
+}
+
+// run calls fn for every id with at most concurrency calls at once
+func (s *batchService) run(req *BatchRequest, fn func(result *BatchResult) error) (*BatchResponse, error) {
+
+	if err := s.validate(req); err != nil {
+		logrus.WithError(err).Error("invalid batch request")
+		return nil, err
+	}
+
+	results := make([]*BatchResult, len(req.IDs))
+	semaphore := make(chan struct{}, s.concurrency)
+	wg := new(sync.WaitGroup)
+
+	for i, id := range req.IDs {
+		results[i] = &BatchResult{ID: id}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(result *BatchResult) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			if err := fn(result); err != nil {
+				result.Error = core.ErrorKey(err)
+			}
+		}(results[i])
+	}
+
+	wg.Wait()
+
+	return &BatchResponse{Results: results}, nil
+}
+
+func (s *batchService) validate(req *BatchRequest) error {
+
+	validation := core.NewValidation()
+
+	if len(req.IDs) < 1 {
+		validation.Add("ids", core.RuleMin, "at least one id is required")
+	}
+
+	if len(req.IDs) > s.maxIDs {
+		validation.Add("ids", core.RuleMax, "too many ids in a single batch")
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+
+	for i, id := range req.IDs {
+		if id == "" {
+			validation.Add(core.IndexPath("ids", i), core.RuleRequired, "id is required")
+		}
+
+		if seen[id] {
+			validation.Add(core.IndexPath("ids", i), core.RuleUnique, "id is repeated")
+		}
+
+		seen[id] = true
+	}
+
+	return validation.Err()
+}
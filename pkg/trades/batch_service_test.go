@@ -0,0 +1,125 @@
+package trades_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type batchServiceTestSuite struct {
+	suite.Suite
+	assert       *assert.Assertions
+	ctx          context.Context
+	tradeService *mock.TradeServiceMock
+	service      trades.BatchService
+}
+
+func TestBatchServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(batchServiceTestSuite))
+}
+
+func (s *batchServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *batchServiceTestSuite) SetupTest() {
+	s.tradeService = mock.NewTradeService().(*mock.TradeServiceMock)
+	s.service = trades.NewBatchService(s.tradeService, &core.Batch{MaxIDs: 10, Concurrency: 2})
+}
+
+func (s *batchServiceTestSuite) TestGet() {
+	userID := uuid.NewString()
+	found, missing := uuid.NewString(), uuid.NewString()
+
+	s.tradeService.On("GetByID", userID, found).Return(&trades.GetTradeOfferResponse{
+		Trade: &trades.TradeOfferModel{ID: found},
+	})
+	s.tradeService.On("GetByID", userID, missing).Return(nil, core.ErrNotFound)
+
+	res, err := s.service.Get(s.ctx, userID, &trades.BatchRequest{IDs: []string{found, missing}})
+
+	s.assert.NoError(err)
+	s.assert.Len(res.Results, 2)
+
+	s.assert.Equal(found, res.Results[0].ID)
+	s.assert.Equal(found, res.Results[0].Trade.ID)
+	s.assert.Empty(res.Results[0].Error)
+
+	s.assert.Equal(missing, res.Results[1].ID)
+	s.assert.Nil(res.Results[1].Trade)
+	s.assert.Equal(core.ErrNotFound.Key, res.Results[1].Error)
+}
+
+func (s *batchServiceTestSuite) TestCancel() {
+	userID := uuid.NewString()
+	ids := make([]string, 5)
+
+	for i := range ids {
+		ids[i] = uuid.NewString()
+
+		var err error
+		if i == 3 {
+			err = errors.New("unexpected")
+		}
+
+		s.tradeService.On("Cancel", userID, ids[i]).Return(err)
+	}
+
+	res, err := s.service.Cancel(s.ctx, userID, uuid.NewString(), &trades.BatchRequest{IDs: ids})
+
+	s.assert.NoError(err)
+
+	for i, result := range res.Results {
+		s.assert.Equal(ids[i], result.ID)
+
+		if i == 3 {
+			s.assert.Equal("internal-server-error", result.Error)
+			continue
+		}
+
+		s.assert.Empty(result.Error)
+	}
+
+	s.tradeService.AssertNumberOfCalls(s.T(), "Cancel", 5)
+}
+
+func (s *batchServiceTestSuite) TestDeclineTooManyIDs() {
+	ids := make([]string, 11)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("trade-%d", i)
+	}
+
+	res, err := s.service.Decline(s.ctx, uuid.NewString(), uuid.NewString(), &trades.BatchRequest{IDs: ids})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+
+	s.tradeService.AssertNumberOfCalls(s.T(), "Decline", 0)
+}
+
+func (s *batchServiceTestSuite) TestDeclineRepeatedIDs() {
+	id := uuid.NewString()
+
+	res, err := s.service.Decline(s.ctx, uuid.NewString(), uuid.NewString(), &trades.BatchRequest{IDs: []string{id, id}})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+}
+
+func (s *batchServiceTestSuite) TestDefaultsKeepSettings() {
+	settings := &core.Batch{}
+
+	trades.NewBatchService(s.tradeService, settings)
+
+	s.assert.Equal(0, settings.MaxIDs)
+	s.assert.Equal(0, settings.Concurrency)
+}
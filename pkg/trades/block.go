@@ -0,0 +1,72 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// Block a user that is not allowed to send offers to the blocker
+type Block struct {
+	ID        string    `bson:"_id"`
+	BlockerID string    `bson:"blocker_id"`
+	BlockedID string    `bson:"blocked_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// ResultBlocks ...
+type ResultBlocks struct {
+	Blocks []*Block
+	Token  string
+}
+
+// BlockRepository ...
+type BlockRepository interface {
+	// Insert does nothing when the user is already blocked
+	Insert(ctx context.Context, block *Block) error
+	// Delete returns core.ErrNotFound when the user was not blocked
+	Delete(ctx context.Context, blockerID, blockedID string) error
+	Get(ctx context.Context, blockerID string, req *GetTradesOffers) (*ResultBlocks, error)
+	Exists(ctx context.Context, blockerID, blockedID string) (bool, error)
+	// GetBlockedIDs returns every user blocked by the blocker
+	GetBlockedIDs(ctx context.Context, blockerID string) ([]string, error)
+}
+
+// BlockService ...
+type BlockService interface {
+	Create(ctx context.Context, userID, correlationID, blockedID string) error
+	Delete(ctx context.Context, userID, correlationID, blockedID string) error
+	Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetBlocksResponse, error)
+}
+
+// NewBlock the id is derived from both users so blocking twice keeps one block
+func NewBlock(blockerID, blockedID string) *Block {
+	return &Block{
+		ID:        blockerID + ":" + blockedID,
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NotBlockedRule rejects offers sent to a user who blocked the owner
+func NotBlockedRule(repository BlockRepository) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		// listings have no recipient and edited offers were already allowed
+		if trade.WantedItemsOwnerID == "" || trade.Status == TradePending {
+			return nil
+		}
+
+		blocked, err := repository.Exists(ctx, trade.WantedItemsOwnerID, trade.OwnerID)
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			return core.ErrTradeBlocked
+		}
+
+		return nil
+	})
+}
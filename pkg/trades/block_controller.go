@@ -0,0 +1,79 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockController ...
+type BlockController struct {
+	authenticate *core.Authenticate
+	service      BlockService
+}
+
+// NewBlockController ...
+func NewBlockController(authenticate *core.Authenticate, service BlockService) BlockController {
+	return BlockController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *BlockController) RegisterRoutes(r *gin.RouterGroup) {
+	blocks := r.Group("/trades/blocks")
+	{
+		blocks.Use(
+			c.authenticate.Middleware(),
+		)
+
+		blocks.POST(":user_id", c.post)
+		blocks.DELETE(":user_id", c.delete)
+		blocks.GET("", c.get)
+	}
+}
+
+func (c *BlockController) post(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := c.service.Create(ctx, userID, correlationID, ctx.Param("user_id")); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *BlockController) delete(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := c.service.Delete(ctx, userID, correlationID, ctx.Param("user_id")); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *BlockController) get(ctx *gin.Context) {
+	req := new(GetTradeOffersRequest)
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, userID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
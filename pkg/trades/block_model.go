@@ -0,0 +1,37 @@
+package trades
+
+import "time"
+
+// BlockModel ...
+type BlockModel struct {
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetBlocksResponse ...
+type GetBlocksResponse struct {
+	Blocks []*BlockModel `json:"blocks"`
+	Token  string        `json:"token"`
+}
+
+// ParseBlock ...
+func ParseBlock(block *Block) *BlockModel {
+	return &BlockModel{
+		UserID:    block.BlockedID,
+		CreatedAt: block.CreatedAt,
+	}
+}
+
+// ParseGetBlocksResponse ...
+func ParseGetBlocksResponse(res *ResultBlocks) *GetBlocksResponse {
+	blocks := make([]*BlockModel, len(res.Blocks))
+
+	for i, block := range res.Blocks {
+		blocks[i] = ParseBlock(block)
+	}
+
+	return &GetBlocksResponse{
+		Token:  res.Token,
+		Blocks: blocks,
+	}
+}
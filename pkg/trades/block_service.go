@@ -0,0 +1,83 @@
+package trades
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+)
+
+type blockService struct {
+	repository BlockRepository
+}
+
+// NewBlockService ...
+func NewBlockService(repository BlockRepository) BlockService {
+	return &blockService{repository: repository}
+}
+
+func (s *blockService) Create(ctx context.Context, userID, correlationID, blockedID string) error {
+
+	fields := logrus.Fields{
+		"user_id":        userID,
+		"blocked_id":     blockedID,
+		"correlation_id": correlationID,
+	}
+
+	if blockedID == userID {
+		validation := core.NewValidation()
+		validation.Add("user_id", core.RuleForbidden, "users cannot block themselves")
+
+		err := validation.Err()
+		logrus.WithError(err).WithFields(fields).Error("invalid block")
+		return err
+	}
+
+	if err := s.repository.Insert(ctx, NewBlock(userID, blockedID)); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error inserting block")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("user blocked")
+
+	return nil
+}
+
+func (s *blockService) Delete(ctx context.Context, userID, correlationID, blockedID string) error {
+
+	fields := logrus.Fields{
+		"user_id":        userID,
+		"blocked_id":     blockedID,
+		"correlation_id": correlationID,
+	}
+
+	if err := s.repository.Delete(ctx, userID, blockedID); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error deleting block")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("user unblocked")
+
+	return nil
+}
+
+func (s *blockService) Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetBlocksResponse, error) {
+
+	res, err := s.repository.Get(ctx, userID, &GetTradesOffers{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	})
+
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"user_id": userID,
+				"token":   req.Token,
+			}).
+			Error("error getting blocks")
+		return nil, err
+	}
+
+	return ParseGetBlocksResponse(res), nil
+}
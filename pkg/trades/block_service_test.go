@@ -0,0 +1,128 @@
+package trades_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type blockServiceTestSuite struct {
+	suite.Suite
+	assert           *assert.Assertions
+	ctx              context.Context
+	repository       *mock.BlockRepositoryMock
+	tradeRepository  *mock.RepositoryMock
+	inventoryService *mock.InventoryServiceMock
+	service          trades.BlockService
+}
+
+func TestBlockServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(blockServiceTestSuite))
+}
+
+func (s *blockServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *blockServiceTestSuite) SetupTest() {
+	s.repository = mock.NewBlockRepository().(*mock.BlockRepositoryMock)
+	s.tradeRepository = mock.NewRepository().(*mock.RepositoryMock)
+	s.inventoryService = mock.NewInventoryService().(*mock.InventoryServiceMock)
+	s.service = trades.NewBlockService(s.repository)
+}
+
+func (s *blockServiceTestSuite) TestCreate() {
+	userID, blockedID := uuid.NewString(), uuid.NewString()
+
+	s.repository.On("Insert", userID, blockedID).Return(nil)
+
+	err := s.service.Create(s.ctx, userID, uuid.NewString(), blockedID)
+
+	s.assert.NoError(err)
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+}
+
+func (s *blockServiceTestSuite) TestCreateSelf() {
+	userID := uuid.NewString()
+
+	err := s.service.Create(s.ctx, userID, uuid.NewString(), userID)
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *blockServiceTestSuite) TestDeleteNotBlocked() {
+	userID, blockedID := uuid.NewString(), uuid.NewString()
+
+	s.repository.On("Delete", userID, blockedID).Return(core.ErrNotFound)
+
+	err := s.service.Delete(s.ctx, userID, uuid.NewString(), blockedID)
+
+	s.assert.ErrorIs(err, core.ErrNotFound)
+}
+
+func (s *blockServiceTestSuite) TestCreateOfferToBlocker() {
+	userID, blockerID := uuid.NewString(), uuid.NewString()
+
+	s.repository.On("Exists", blockerID, userID).Return(true, nil)
+
+	service := trades.NewService(
+		s.tradeRepository,
+		s.inventoryService,
+		trades.WithRuleEngine(trades.NewDefaultRuleEngine(s.tradeRepository, nil).With(
+			trades.NotBlockedRule(s.repository),
+		)),
+	)
+
+	res, err := service.Create(s.ctx, userID, uuid.NewString(), &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: blockerID,
+		OfferedItems:       []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		WantedItems:        []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+	})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrTradeBlocked)
+
+	s.tradeRepository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *blockServiceTestSuite) TestClaimListingOfBlocker() {
+	userID := uuid.NewString()
+	trade := newListedTrade()
+
+	s.repository.On("Exists", trade.OwnerID, userID).Return(true, nil)
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+
+	service := trades.NewService(s.tradeRepository, s.inventoryService, trades.WithBlockRepository(s.repository))
+
+	err := service.Claim(s.ctx, userID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrTradeBlocked)
+
+	s.tradeRepository.AssertNumberOfCalls(s.T(), "Claim", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *blockServiceTestSuite) TestGetTradesHidesBlockedSenders() {
+	userID, blockedID := uuid.NewString(), uuid.NewString()
+
+	s.repository.On("GetBlockedIDs", userID).Return([]string{blockedID})
+	s.tradeRepository.On("Get", userID, []string{blockedID}).Return(&trades.ResultTradeOffers{
+		Trades: []*trades.TradeOffer{},
+	})
+
+	service := trades.NewService(s.tradeRepository, s.inventoryService, trades.WithBlockRepository(s.repository))
+
+	res, err := service.Get(s.ctx, userID, &trades.GetTradeOffersRequest{})
+
+	s.assert.NoError(err)
+	s.assert.Empty(res.Trades)
+	s.tradeRepository.AssertNumberOfCalls(s.T(), "Get", 1)
+}
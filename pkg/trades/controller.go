@@ -1,6 +1,7 @@
 package trades
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/d-leme/tradew-trades/pkg/core"
@@ -31,7 +32,14 @@ func (c *Controller) RegisterRoutes(r *gin.RouterGroup) {
 
 		trades.POST("", c.post)
 		trades.POST("accept/:id", c.accept)
+		trades.POST("claim/:id", c.claim)
+		trades.POST("submit/:id", c.submit)
+		trades.POST("cancel/:id", c.cancel)
+		trades.POST("decline/:id", c.decline)
+		trades.POST("instantiate/:id", c.instantiate)
+		trades.PATCH(":id", c.patch)
 		trades.GET("", c.get)
+		trades.GET("listings", c.getListings)
 		trades.GET(":id", c.getByID)
 	}
 }
@@ -57,11 +65,18 @@ func (c *Controller) post(ctx *gin.Context) {
 }
 
 func (c *Controller) accept(ctx *gin.Context) {
+	req := new(AcceptTradeOfferRequest)
 	correlationID := ctx.GetString("X-Correlation-ID")
 	userID := ctx.GetString("user_id")
 	id := ctx.Param("id")
 
-	if err := c.service.Accept(ctx, userID, correlationID, id); err != nil {
+	// the body is optional, older clients accept without expectations
+	if err := ctx.ShouldBindJSON(req); err != nil && err != io.EOF {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	if err := c.service.Accept(ctx, userID, correlationID, id, req); err != nil {
 		core.HandleRestError(ctx, err)
 		return
 	}
@@ -69,6 +84,98 @@ func (c *Controller) accept(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+func (c *Controller) claim(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.service.Claim(ctx, userID, correlationID, id); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) patch(ctx *gin.Context) {
+	req := new(UpdateTradeOfferRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	if err := c.service.Update(ctx, userID, correlationID, id, req); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) cancel(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.service.Cancel(ctx, userID, correlationID, id); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) decline(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.service.Decline(ctx, userID, correlationID, id); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) submit(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.service.Submit(ctx, userID, correlationID, id); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) instantiate(ctx *gin.Context) {
+	req := new(InstantiateTemplateRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Instantiate(ctx, userID, correlationID, id, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
 func (c *Controller) get(ctx *gin.Context) {
 	req := new(GetTradeOffersRequest)
 	userID := ctx.GetString("user_id")
@@ -101,3 +208,21 @@ func (c *Controller) getByID(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *Controller) getListings(ctx *gin.Context) {
+	req := new(GetTradeListingsRequest)
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.GetListings(ctx, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,73 @@
+package trades
+
+import "github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
+
+// tradeDiff items and currency that changed on each side of an edited offer
+type tradeDiff struct {
+	offeredItems    []*Item
+	wantedItems     []*Item
+	offeredCurrency int64
+	wantedCurrency  int64
+}
+
+// diffTradeOffers returns what has to be locked and unlocked to move the
+// locks of before to after
+func diffTradeOffers(before, after *TradeOffer) (added, removed *tradeDiff) {
+	added, removed = new(tradeDiff), new(tradeDiff)
+
+	added.offeredItems, removed.offeredItems = diffItems(before.OfferedItems, after.OfferedItems)
+	added.wantedItems, removed.wantedItems = diffItems(before.WantedItems, after.WantedItems)
+
+	added.offeredCurrency, removed.offeredCurrency = diffCurrency(before.OfferedCurrency, after.OfferedCurrency)
+	added.wantedCurrency, removed.wantedCurrency = diffCurrency(before.WantedCurrency, after.WantedCurrency)
+
+	return added, removed
+}
+
+func (d *tradeDiff) empty() bool {
+	return len(d.offeredItems) < 1 && len(d.wantedItems) < 1 && d.offeredCurrency < 1 && d.wantedCurrency < 1
+}
+
+func (d *tradeDiff) lockItemsRequest(trade *TradeOffer) *inventory.LockItemsRequest {
+	return &inventory.LockItemsRequest{
+		LockedBy:           trade.ID,
+		OwnerID:            trade.OwnerID,
+		WantedItemsOwnerID: trade.WantedItemsOwnerID,
+		OfferedItems:       toItemsToLock(d.offeredItems),
+		WantedItems:        toItemsToLock(d.wantedItems),
+		OfferedCurrency:    d.offeredCurrency,
+		WantedCurrency:     d.wantedCurrency,
+	}
+}
+
+func diffItems(before, after []*Item) (added, removed []*Item) {
+	quantities := make(map[string]int64, len(before))
+	for _, item := range before {
+		quantities[item.ID] += item.Quantity
+	}
+
+	for _, item := range after {
+		if delta := item.Quantity - quantities[item.ID]; delta > 0 {
+			added = append(added, &Item{ID: item.ID, Quantity: delta})
+		}
+
+		quantities[item.ID] -= item.Quantity
+	}
+
+	for _, item := range before {
+		if delta := quantities[item.ID]; delta > 0 {
+			removed = append(removed, &Item{ID: item.ID, Quantity: delta})
+			quantities[item.ID] = 0
+		}
+	}
+
+	return added, removed
+}
+
+func diffCurrency(before, after int64) (added, removed int64) {
+	if after > before {
+		return after - before, 0
+	}
+
+	return 0, before - after
+}
@@ -2,6 +2,10 @@ package trades
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/d-leme/tradew-trades/pkg/core"
@@ -25,8 +29,47 @@ const (
 
 	// TradeError ...
 	TradeError TradeStatus = "Error"
+
+	// TradeListed public listing waiting for a counterparty to claim it
+	TradeListed TradeStatus = "Listed"
+
+	// TradeDraft saved by the owner but not sent, items are not locked
+	TradeDraft TradeStatus = "Draft"
+
+	// TradeTemplate reusable offer content without counterparty
+	TradeTemplate TradeStatus = "Template"
+
+	// TradeCancelled withdrawn by the owner, items are unlocked
+	TradeCancelled TradeStatus = "Cancelled"
+
+	// TradeDeclined refused by the counterparty, items are unlocked
+	TradeDeclined TradeStatus = "Declined"
+
+	// TradeInvalidated can no longer be traded because the inventory changed,
+	// the reason tells which change
+	TradeInvalidated TradeStatus = "Invalidated"
 )
 
+const (
+	// ReasonItemRemoved an item of the offer was removed from the inventory
+	ReasonItemRemoved = "item-removed"
+
+	// ReasonItemQuantityDropped the inventory no longer has the quantity the offer needs
+	ReasonItemQuantityDropped = "item-quantity-dropped"
+)
+
+// RuleOneSided violated when currency is added to both sides of an offer
+const RuleOneSided = "one-sided"
+
+// OpenTradeStatuses statuses of offers that were not finished yet
+var OpenTradeStatuses = []TradeStatus{TradeCreated, TradePending, TradeAccepted, TradeListed}
+
+// FinishedTradeStatuses statuses of offers that will not change anymore
+var FinishedTradeStatuses = []TradeStatus{TradeCompleted, TradeError, TradeCancelled, TradeDeclined, TradeInvalidated}
+
+// InvalidatableTradeStatuses statuses of offers whose items are locked but not traded yet
+var InvalidatableTradeStatuses = []TradeStatus{TradePending, TradeListed}
+
 // Item ...
 type Item struct {
 	ID       string `bson:"id"`
@@ -41,14 +84,29 @@ type TradeOffer struct {
 	Status             TradeStatus `bson:"status"`
 	OfferedItems       []*Item     `bson:"offered_items"`
 	WantedItems        []*Item     `bson:"wanted_items"`
+	OfferedCurrency    int64       `bson:"offered_currency"`
+	WantedCurrency     int64       `bson:"wanted_currency"`
+	Public             bool        `bson:"public"`
+	Revision           int64       `bson:"revision"`
+	Reason             string      `bson:"reason,omitempty"`
 	CreatedAt          time.Time   `bson:"created_at"`
 	UpdatedAt          *time.Time  `bson:"updated_at"`
 }
 
+// ItemChange new quantity of an inventory item, removed items have none
+type ItemChange struct {
+	ItemID   string
+	OwnerID  string
+	Quantity int64
+	Removed  bool
+}
+
 // GetTradesOffers ...
 type GetTradesOffers struct {
 	Token    *string
 	PageSize int64
+	// BlockedOwnerIDs pending offers sent by these users are hidden from the recipient
+	BlockedOwnerIDs []string
 }
 
 // ResultTradeOffers ...
@@ -57,31 +115,85 @@ type ResultTradeOffers struct {
 	Token  string
 }
 
+// GetTradeListings ...
+type GetTradeListings struct {
+	OfferedItemID string
+	WantedItemID  string
+	Token         *string
+	PageSize      int64
+}
+
+// CountTradesOffers ...
+type CountTradesOffers struct {
+	OwnerID            string
+	WantedItemsOwnerID string
+	Statuses           []TradeStatus
+}
+
 // Repository ...
 type Repository interface {
 	Insert(ctx context.Context, trade *TradeOffer) error
 	Update(ctx context.Context, trade *TradeOffer) error
 	Get(ctx context.Context, userID string, req *GetTradesOffers) (*ResultTradeOffers, error)
 	GetByID(ctx context.Context, userID string, id string) (*TradeOffer, error)
+	Count(ctx context.Context, req *CountTradesOffers) (int64, error)
+	GetListings(ctx context.Context, req *GetTradeListings) (*ResultTradeOffers, error)
+
+	// GetByStatus pages through the offers of every user in one of the statuses
+	GetByStatus(ctx context.Context, statuses []TradeStatus, req *GetTradesOffers) (*ResultTradeOffers, error)
+
+	// Claim atomically binds the user as counterparty of a listed offer and returns
+	// the updated offer, core.ErrNotFound is returned if it was already claimed
+	Claim(ctx context.Context, userID, id string, claimedAt time.Time) (*TradeOffer, error)
+
+	// Stats aggregates the offers sent and received by the user
+	Stats(ctx context.Context, userID string, req *GetTradeStats) (*TradeStats, error)
+
+	// Replace persists the whole offer only if the stored status and revision
+	// still equal from and revision
+	Replace(ctx context.Context, trade *TradeOffer, from TradeStatus, revision int64) (bool, error)
+
+	// GetByItem pages through the offers in one of the statuses with the item on any side
+	GetByItem(ctx context.Context, itemID string, statuses []TradeStatus, req *GetTradesOffers) (*ResultTradeOffers, error)
 }
 
 // Service ...
 type Service interface {
 	Create(ctx context.Context, userID, correlationID string, req *CreateTradeOfferRequest) (*CreateTradeOfferResponse, error)
-	Accept(ctx context.Context, userID, correlationID, id string) error
+	Accept(ctx context.Context, userID, correlationID, id string, req *AcceptTradeOfferRequest) error
 	Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetTradeOffersResponse, error)
 	GetByID(ctx context.Context, userID, id string) (*GetTradeOfferResponse, error)
+	GetListings(ctx context.Context, req *GetTradeListingsRequest) (*GetTradeOffersResponse, error)
+	Claim(ctx context.Context, userID, correlationID, id string) error
+	Update(ctx context.Context, userID, correlationID, id string, req *UpdateTradeOfferRequest) error
+	Submit(ctx context.Context, userID, correlationID, id string) error
+	Instantiate(ctx context.Context, userID, correlationID, id string, req *InstantiateTemplateRequest) (*CreateTradeOfferResponse, error)
+	Cancel(ctx context.Context, userID, correlationID, id string) error
+	Decline(ctx context.Context, userID, correlationID, id string) error
+
+	// InvalidateItem closes the offers that can no longer be traded after the change
+	InvalidateItem(ctx context.Context, correlationID string, change *ItemChange) error
+
+	// TradeLocked trades an offer whose items are already locked under its id,
+	// the creation rules are skipped since both sides committed the items before
+	TradeLocked(ctx context.Context, correlationID string, trade *TradeOffer) error
 }
 
 // NewItem ...
 func NewItem(id string, quantity int64) (*Item, error) {
 
+	validation := core.NewValidation()
+
 	if id == "" {
-		return nil, core.ErrValidationFailed
+		validation.Add("id", core.RuleRequired, "id is required")
 	}
 
 	if quantity < 1 {
-		return nil, core.ErrValidationFailed
+		validation.Add("quantity", core.RuleMin, "quantity must be at least 1")
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
 	}
 
 	return &Item{
@@ -90,40 +202,306 @@ func NewItem(id string, quantity int64) (*Item, error) {
 	}, nil
 }
 
-// NewTradeOffer ...
-func NewTradeOffer(id, ownerID, wantedItemsOwnerID string, offeredItems, wantedItems []*Item) (*TradeOffer, error) {
+// NewTradeOffer creates an offer where each side holds items, currency or both
+func NewTradeOffer(
+	id, ownerID, wantedItemsOwnerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) (*TradeOffer, error) {
 
-	if id == "" {
-		return nil, core.ErrValidationFailed
-	}
+	validation := core.NewValidation()
 
-	if ownerID == "" {
-		return nil, core.ErrValidationFailed
+	if wantedItemsOwnerID == "" {
+		validation.Add("wanted_items_owner_id", core.RuleRequired, "wanted items owner id is required")
 	}
 
-	if wantedItemsOwnerID == "" {
-		return nil, core.ErrValidationFailed
+	validateTradeOffer(validation, id, ownerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency)
+
+	if err := validation.Err(); err != nil {
+		return nil, err
 	}
 
-	if len(offeredItems) < 1 {
-		return nil, core.ErrValidationFailed
+	return &TradeOffer{
+		ID:                 id,
+		OwnerID:            ownerID,
+		WantedItemsOwnerID: wantedItemsOwnerID,
+		Status:             TradeCreated,
+		OfferedItems:       offeredItems,
+		WantedItems:        wantedItems,
+		OfferedCurrency:    offeredCurrency,
+		WantedCurrency:     wantedCurrency,
+		Revision:           1,
+		CreatedAt:          time.Now(),
+	}, nil
+}
+
+// NewTradeListing creates a public offer without counterparty, the first
+// user to claim it becomes the wanted items owner
+func NewTradeListing(
+	id, ownerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) (*TradeOffer, error) {
+
+	validation := core.NewValidation()
+
+	validateTradeOffer(validation, id, ownerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency)
+
+	if err := validation.Err(); err != nil {
+		return nil, err
 	}
 
-	if len(wantedItems) < 1 {
-		return nil, core.ErrValidationFailed
+	return &TradeOffer{
+		ID:              id,
+		OwnerID:         ownerID,
+		Status:          TradeCreated,
+		OfferedItems:    offeredItems,
+		WantedItems:     wantedItems,
+		OfferedCurrency: offeredCurrency,
+		WantedCurrency:  wantedCurrency,
+		Public:          true,
+		Revision:        1,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// NewTradeDraft creates an offer that is only validated when submitted
+func NewTradeDraft(
+	id, ownerID, wantedItemsOwnerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) (*TradeOffer, error) {
+
+	validation := core.NewValidation()
+
+	validateTradeDraft(validation, id, ownerID, offeredCurrency, wantedCurrency)
+
+	if err := validation.Err(); err != nil {
+		return nil, err
 	}
 
 	return &TradeOffer{
 		ID:                 id,
 		OwnerID:            ownerID,
 		WantedItemsOwnerID: wantedItemsOwnerID,
-		Status:             TradeCreated,
+		Status:             TradeDraft,
 		OfferedItems:       offeredItems,
 		WantedItems:        wantedItems,
+		OfferedCurrency:    offeredCurrency,
+		WantedCurrency:     wantedCurrency,
+		Revision:           1,
 		CreatedAt:          time.Now(),
 	}, nil
 }
 
+// NewTradeTemplate creates reusable offer content, the counterparty is set
+// every time the template is instantiated
+func NewTradeTemplate(
+	id, ownerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) (*TradeOffer, error) {
+
+	validation := core.NewValidation()
+
+	validateTradeOffer(validation, id, ownerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency)
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	return &TradeOffer{
+		ID:              id,
+		OwnerID:         ownerID,
+		Status:          TradeTemplate,
+		OfferedItems:    offeredItems,
+		WantedItems:     wantedItems,
+		OfferedCurrency: offeredCurrency,
+		WantedCurrency:  wantedCurrency,
+		Revision:        1,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// Editable drafts and templates can be changed by their owner
+func (trade *TradeOffer) Editable() bool {
+	return trade.Status == TradeDraft || trade.Status == TradeTemplate
+}
+
+// Edit replaces the content of a draft, template or pending offer validating
+// it the same way it was validated when created and bumps the revision
+func (trade *TradeOffer) Edit(
+	wantedItemsOwnerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) error {
+
+	validation := core.NewValidation()
+
+	switch trade.Status {
+	case TradeDraft:
+		validateTradeDraft(validation, trade.ID, trade.OwnerID, offeredCurrency, wantedCurrency)
+	case TradeTemplate:
+		if wantedItemsOwnerID != "" {
+			validation.Add("wanted_items_owner_id", core.RuleForbidden, "templates can't have a wanted items owner")
+		}
+
+		validateTradeOffer(validation, trade.ID, trade.OwnerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency)
+	case TradePending:
+		// items of the counterparty are already locked in its name
+		if wantedItemsOwnerID != trade.WantedItemsOwnerID {
+			validation.Add("wanted_items_owner_id", core.RuleForbidden, "the counterparty of a pending offer can't change")
+		}
+
+		validateTradeOffer(validation, trade.ID, trade.OwnerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency)
+	default:
+		return core.ErrTradeInvalidStatus
+	}
+
+	if err := validation.Err(); err != nil {
+		return err
+	}
+
+	trade.WantedItemsOwnerID = wantedItemsOwnerID
+	trade.OfferedItems = offeredItems
+	trade.WantedItems = wantedItems
+	trade.OfferedCurrency = offeredCurrency
+	trade.WantedCurrency = wantedCurrency
+	trade.Revision++
+
+	now := time.Now()
+	trade.UpdatedAt = &now
+
+	return nil
+}
+
+func validateTradeDraft(
+	validation *core.Validation,
+	id, ownerID string,
+	offeredCurrency, wantedCurrency int64,
+) {
+
+	if id == "" {
+		validation.Add("id", core.RuleRequired, "id is required")
+	}
+
+	if ownerID == "" {
+		validation.Add("owner_id", core.RuleRequired, "owner id is required")
+	}
+
+	if offeredCurrency < 0 {
+		validation.Add("offered_currency", core.RuleMin, "offered currency can't be negative")
+	}
+
+	if wantedCurrency < 0 {
+		validation.Add("wanted_currency", core.RuleMin, "wanted currency can't be negative")
+	}
+}
+
+func validateTradeOffer(
+	validation *core.Validation,
+	id, ownerID string,
+	offeredItems, wantedItems []*Item,
+	offeredCurrency, wantedCurrency int64,
+) {
+
+	if id == "" {
+		validation.Add("id", core.RuleRequired, "id is required")
+	}
+
+	if ownerID == "" {
+		validation.Add("owner_id", core.RuleRequired, "owner id is required")
+	}
+
+	if offeredCurrency < 0 {
+		validation.Add("offered_currency", core.RuleMin, "offered currency can't be negative")
+	}
+
+	if wantedCurrency < 0 {
+		validation.Add("wanted_currency", core.RuleMin, "wanted currency can't be negative")
+	}
+
+	if offeredCurrency > 0 && wantedCurrency > 0 {
+		validation.Add("wanted_currency", RuleOneSided, "currency can only be added to one side")
+	}
+
+	if len(offeredItems) < 1 && offeredCurrency < 1 {
+		validation.Add("offered_items", core.RuleMin, "at least one offered item or currency is required")
+	}
+
+	if len(wantedItems) < 1 && wantedCurrency < 1 {
+		validation.Add("wanted_items", core.RuleMin, "at least one wanted item or currency is required")
+	}
+
+	if len(offeredItems) < 1 && len(wantedItems) < 1 {
+		validation.Add("offered_items", core.RuleMin, "at least one item must be traded")
+	}
+}
+
+// ContentHash canonical hash of the items and currency of both sides, the
+// order of the items doesn't change it
+func (trade *TradeOffer) ContentHash() string {
+	h := sha256.New()
+
+	for _, side := range []struct {
+		name     string
+		items    []*Item
+		currency int64
+	}{
+		{"offered", trade.OfferedItems, trade.OfferedCurrency},
+		{"wanted", trade.WantedItems, trade.WantedCurrency},
+	} {
+		items := make([]*Item, len(side.items))
+		copy(items, side.items)
+
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].ID < items[j].ID
+		})
+
+		fmt.Fprintf(h, "%s_currency=%d\n", side.name, side.currency)
+
+		for _, item := range items {
+			fmt.Fprintf(h, "%s_item=%q:%d\n", side.name, item.ID, item.Quantity)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// InvalidatedBy returns the reason the change invalidates the offer or an
+// empty string when the owner of the item still has enough of it
+func (trade *TradeOffer) InvalidatedBy(change *ItemChange) string {
+	var items []*Item
+
+	// public listings have no counterparty yet, so an empty owner matches none
+	switch change.OwnerID {
+	case "":
+		return ""
+	case trade.OwnerID:
+		items = trade.OfferedItems
+	case trade.WantedItemsOwnerID:
+		items = trade.WantedItems
+	default:
+		return ""
+	}
+
+	for _, item := range items {
+		if item.ID != change.ItemID {
+			continue
+		}
+
+		if change.Removed {
+			return ReasonItemRemoved
+		}
+
+		if item.Quantity > change.Quantity {
+			return ReasonItemQuantityDropped
+		}
+	}
+
+	return ""
+}
+
 // UpdateStatus ...
 func (trade *TradeOffer) UpdateStatus(status TradeStatus) {
 	trade.Status = status
@@ -0,0 +1,61 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// TradeOfferUpdatedEventType type of TradeOfferUpdatedEvent envelopes
+const TradeOfferUpdatedEventType = "trade-offer-updated"
+
+// Producer publishes enveloped events to a message broker topic, events of
+// a trade share its id as group id so FIFO topics deliver them in order
+type Producer interface {
+	Publish(topicID string, event *core.Event) (string, error)
+}
+
+// TradeOfferUpdatedEvent published when the owner edits a pending offer so
+// the counterparty knows the revision it viewed is stale
+type TradeOfferUpdatedEvent struct {
+	TradeID            string    `json:"trade_id"`
+	OwnerID            string    `json:"owner_id"`
+	WantedItemsOwnerID string    `json:"wanted_items_owner_id"`
+	Revision           int64     `json:"revision"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// TradeOutcome how a trade offer or auction finished
+type TradeOutcome string
+
+const (
+	// OutcomeCompleted the items were traded
+	OutcomeCompleted TradeOutcome = "completed"
+
+	// OutcomeDeclined the counterparty declined the offer
+	OutcomeDeclined TradeOutcome = "declined"
+
+	// OutcomeCancelled the owner cancelled the offer
+	OutcomeCancelled TradeOutcome = "cancelled"
+
+	// OutcomeExpired the auction deadline passed without bids
+	OutcomeExpired TradeOutcome = "expired"
+
+	// OutcomeErrored the items could not be locked or traded
+	OutcomeErrored TradeOutcome = "errored"
+)
+
+// Transition a trade offer or auction reaching a final status
+type Transition struct {
+	TradeID        string
+	OwnerID        string
+	CounterpartyID string
+	Outcome        TradeOutcome
+	OccurredAt     time.Time
+}
+
+// TransitionHandler consumes trade lifecycle transitions
+type TransitionHandler interface {
+	HandleTransition(ctx context.Context, transition *Transition) error
+}
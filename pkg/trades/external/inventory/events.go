@@ -0,0 +1,22 @@
+package inventory
+
+const (
+	// ItemChangedEventType type of ItemChangedEvent envelopes
+	ItemChangedEventType = "item-changed"
+
+	// ItemRemovedEventType type of ItemRemovedEvent envelopes
+	ItemRemovedEventType = "item-removed"
+)
+
+// ItemChangedEvent published by the inventory when the quantity of an item changes
+type ItemChangedEvent struct {
+	ID       string `json:"id"`
+	OwnerID  string `json:"owner_id"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ItemRemovedEvent published by the inventory when an item is deleted
+type ItemRemovedEvent struct {
+	ID      string `json:"id"`
+	OwnerID string `json:"owner_id"`
+}
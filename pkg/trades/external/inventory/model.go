@@ -10,6 +10,12 @@ type ItemToLock struct {
 	Quantity int64
 }
 
+// ParticipantItemsToLock items of a single owner in a multi-party trade
+type ParticipantItemsToLock struct {
+	OwnerID string
+	Items   []*ItemToLock
+}
+
 // LockItemsRequest ...
 type LockItemsRequest struct {
 	LockedBy           string
@@ -17,6 +23,9 @@ type LockItemsRequest struct {
 	WantedItemsOwnerID string
 	OfferedItems       []*ItemToLock
 	WantedItems        []*ItemToLock
+	Participants       []*ParticipantItemsToLock
+	OfferedCurrency    int64
+	WantedCurrency     int64
 }
 
 // ItemToTrade ...
@@ -25,6 +34,13 @@ type ItemToTrade struct {
 	Quantity int64
 }
 
+// ParticipantItemsToTrade items given and received by a single owner in a multi-party trade
+type ParticipantItemsToTrade struct {
+	OwnerID       string
+	GivenItems    []*ItemToTrade
+	ReceivedItems []*ItemToTrade
+}
+
 // TradeItemsRequest ...
 type TradeItemsRequest struct {
 	TradeID            string
@@ -32,10 +48,23 @@ type TradeItemsRequest struct {
 	WantedItemsOwnerID string
 	OfferedItems       []*ItemToTrade
 	WantedItems        []*ItemToTrade
+	Participants       []*ParticipantItemsToTrade
+	OfferedCurrency    int64
+	WantedCurrency     int64
+}
+
+// UnlockItemsRequest releases part of a lock when an owner and items or
+// currency are given, otherwise everything locked by LockedBy is released
+type UnlockItemsRequest struct {
+	LockedBy string
+	OwnerID  string
+	Items    []*ItemToLock
+	Currency int64
 }
 
 // Service ...
 type Service interface {
 	LockItems(ctx context.Context, req *LockItemsRequest) error
 	TradesItems(ctx context.Context, req *TradeItemsRequest) error
+	UnlockItems(ctx context.Context, req *UnlockItemsRequest) error
 }
@@ -25,6 +25,9 @@ func (s *service) LockItems(ctx context.Context, req *inventory.LockItemsRequest
 		WantedItemsOwnerID: req.WantedItemsOwnerID,
 		OfferedItems:       make([]*ItemToLock, len(req.OfferedItems)),
 		WantedItems:        make([]*ItemToLock, len(req.WantedItems)),
+		Participants:       make([]*ParticipantItemsToLock, len(req.Participants)),
+		OfferedCurrency:    req.OfferedCurrency,
+		WantedCurrency:     req.WantedCurrency,
 	}
 
 	for i, item := range req.OfferedItems {
@@ -41,6 +44,13 @@ func (s *service) LockItems(ctx context.Context, req *inventory.LockItemsRequest
 		}
 	}
 
+	for i, participant := range req.Participants {
+		protoReq.Participants[i] = &ParticipantItemsToLock{
+			OwnerID: participant.OwnerID,
+			Items:   parseItemsToLock(participant.Items),
+		}
+	}
+
 	if _, err := s.client.LockItems(context.Background(), protoReq); err != nil {
 		return err
 	}
@@ -66,12 +76,24 @@ func (s *service) TradesItems(ctx context.Context, req *inventory.TradeItemsRequ
 		}
 	}
 
+	participants := make([]*ParticipantItemsToTrade, len(req.Participants))
+	for i, participant := range req.Participants {
+		participants[i] = &ParticipantItemsToTrade{
+			OwnerID:       participant.OwnerID,
+			GivenItems:    parseItemsToTrade(participant.GivenItems),
+			ReceivedItems: parseItemsToTrade(participant.ReceivedItems),
+		}
+	}
+
 	protoReq := &TradeItemsRequest{
 		TradeID:            req.TradeID,
 		OwnerID:            req.OwnerID,
 		WantedItemsOwnerID: req.WantedItemsOwnerID,
 		OfferedItems:       offeredItems,
 		WantedItems:        wantedItems,
+		Participants:       participants,
+		OfferedCurrency:    req.OfferedCurrency,
+		WantedCurrency:     req.WantedCurrency,
 	}
 
 	if _, err := s.client.TradeItems(context.Background(), protoReq); err != nil {
@@ -80,3 +102,45 @@ func (s *service) TradesItems(ctx context.Context, req *inventory.TradeItemsRequ
 
 	return nil
 }
+
+func (s *service) UnlockItems(ctx context.Context, req *inventory.UnlockItemsRequest) error {
+
+	protoReq := &UnlockItemsRequest{
+		LockedBy: req.LockedBy,
+		OwnerID:  req.OwnerID,
+		Items:    parseItemsToLock(req.Items),
+		Currency: req.Currency,
+	}
+
+	if _, err := s.client.UnlockItems(context.Background(), protoReq); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func parseItemsToLock(s []*inventory.ItemToLock) []*ItemToLock {
+	items := make([]*ItemToLock, len(s))
+
+	for i, item := range s {
+		items[i] = &ItemToLock{
+			Id:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return items
+}
+
+func parseItemsToTrade(s []*inventory.ItemToTrade) []*ItemToTrade {
+	items := make([]*ItemToTrade, len(s))
+
+	for i, item := range s {
+		items[i] = &ItemToTrade{
+			Id:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return items
+}
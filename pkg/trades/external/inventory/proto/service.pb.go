@@ -113,22 +113,80 @@ func (x *ItemToLock) GetQuantity() int64 {
 	return 0
 }
 
+type ParticipantItemsToLock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OwnerID string        `protobuf:"bytes,1,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	Items   []*ItemToLock `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ParticipantItemsToLock) Reset() {
+	*x = ParticipantItemsToLock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantItemsToLock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantItemsToLock) ProtoMessage() {}
+
+func (x *ParticipantItemsToLock) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantItemsToLock.ProtoReflect.Descriptor instead.
+func (*ParticipantItemsToLock) Descriptor() ([]byte, []int) {
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ParticipantItemsToLock) GetOwnerID() string {
+	if x != nil {
+		return x.OwnerID
+	}
+	return ""
+}
+
+func (x *ParticipantItemsToLock) GetItems() []*ItemToLock {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 type LockItemsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	LockedBy           string        `protobuf:"bytes,1,opt,name=lockedBy,proto3" json:"lockedBy,omitempty"`
-	OwnerID            string        `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
-	WantedItemsOwnerID string        `protobuf:"bytes,3,opt,name=wantedItemsOwnerID,proto3" json:"wantedItemsOwnerID,omitempty"`
-	OfferedItems       []*ItemToLock `protobuf:"bytes,4,rep,name=offeredItems,proto3" json:"offeredItems,omitempty"`
-	WantedItems        []*ItemToLock `protobuf:"bytes,5,rep,name=wantedItems,proto3" json:"wantedItems,omitempty"`
+	LockedBy           string                    `protobuf:"bytes,1,opt,name=lockedBy,proto3" json:"lockedBy,omitempty"`
+	OwnerID            string                    `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	WantedItemsOwnerID string                    `protobuf:"bytes,3,opt,name=wantedItemsOwnerID,proto3" json:"wantedItemsOwnerID,omitempty"`
+	OfferedItems       []*ItemToLock             `protobuf:"bytes,4,rep,name=offeredItems,proto3" json:"offeredItems,omitempty"`
+	WantedItems        []*ItemToLock             `protobuf:"bytes,5,rep,name=wantedItems,proto3" json:"wantedItems,omitempty"`
+	Participants       []*ParticipantItemsToLock `protobuf:"bytes,6,rep,name=participants,proto3" json:"participants,omitempty"`
+	OfferedCurrency    int64                     `protobuf:"varint,7,opt,name=offeredCurrency,proto3" json:"offeredCurrency,omitempty"`
+	WantedCurrency     int64                     `protobuf:"varint,8,opt,name=wantedCurrency,proto3" json:"wantedCurrency,omitempty"`
 }
 
 func (x *LockItemsRequest) Reset() {
 	*x = LockItemsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[2]
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -141,7 +199,7 @@ func (x *LockItemsRequest) String() string {
 func (*LockItemsRequest) ProtoMessage() {}
 
 func (x *LockItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[2]
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -154,7 +212,7 @@ func (x *LockItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LockItemsRequest.ProtoReflect.Descriptor instead.
 func (*LockItemsRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{2}
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *LockItemsRequest) GetLockedBy() string {
@@ -192,6 +250,27 @@ func (x *LockItemsRequest) GetWantedItems() []*ItemToLock {
 	return nil
 }
 
+func (x *LockItemsRequest) GetParticipants() []*ParticipantItemsToLock {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *LockItemsRequest) GetOfferedCurrency() int64 {
+	if x != nil {
+		return x.OfferedCurrency
+	}
+	return 0
+}
+
+func (x *LockItemsRequest) GetWantedCurrency() int64 {
+	if x != nil {
+		return x.WantedCurrency
+	}
+	return 0
+}
+
 type ItemToTrade struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -204,7 +283,7 @@ type ItemToTrade struct {
 func (x *ItemToTrade) Reset() {
 	*x = ItemToTrade{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[3]
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -217,7 +296,7 @@ func (x *ItemToTrade) String() string {
 func (*ItemToTrade) ProtoMessage() {}
 
 func (x *ItemToTrade) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[3]
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,7 +309,7 @@ func (x *ItemToTrade) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ItemToTrade.ProtoReflect.Descriptor instead.
 func (*ItemToTrade) Descriptor() ([]byte, []int) {
-	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{3}
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ItemToTrade) GetId() string {
@@ -247,22 +326,88 @@ func (x *ItemToTrade) GetQuantity() int64 {
 	return 0
 }
 
+type ParticipantItemsToTrade struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OwnerID       string         `protobuf:"bytes,1,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	GivenItems    []*ItemToTrade `protobuf:"bytes,2,rep,name=givenItems,proto3" json:"givenItems,omitempty"`
+	ReceivedItems []*ItemToTrade `protobuf:"bytes,3,rep,name=receivedItems,proto3" json:"receivedItems,omitempty"`
+}
+
+func (x *ParticipantItemsToTrade) Reset() {
+	*x = ParticipantItemsToTrade{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantItemsToTrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantItemsToTrade) ProtoMessage() {}
+
+func (x *ParticipantItemsToTrade) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantItemsToTrade.ProtoReflect.Descriptor instead.
+func (*ParticipantItemsToTrade) Descriptor() ([]byte, []int) {
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ParticipantItemsToTrade) GetOwnerID() string {
+	if x != nil {
+		return x.OwnerID
+	}
+	return ""
+}
+
+func (x *ParticipantItemsToTrade) GetGivenItems() []*ItemToTrade {
+	if x != nil {
+		return x.GivenItems
+	}
+	return nil
+}
+
+func (x *ParticipantItemsToTrade) GetReceivedItems() []*ItemToTrade {
+	if x != nil {
+		return x.ReceivedItems
+	}
+	return nil
+}
+
 type TradeItemsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TradeID            string         `protobuf:"bytes,1,opt,name=tradeID,proto3" json:"tradeID,omitempty"`
-	OwnerID            string         `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
-	WantedItemsOwnerID string         `protobuf:"bytes,3,opt,name=wantedItemsOwnerID,proto3" json:"wantedItemsOwnerID,omitempty"`
-	OfferedItems       []*ItemToTrade `protobuf:"bytes,4,rep,name=offeredItems,proto3" json:"offeredItems,omitempty"`
-	WantedItems        []*ItemToTrade `protobuf:"bytes,5,rep,name=wantedItems,proto3" json:"wantedItems,omitempty"`
+	TradeID            string                     `protobuf:"bytes,1,opt,name=tradeID,proto3" json:"tradeID,omitempty"`
+	OwnerID            string                     `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	WantedItemsOwnerID string                     `protobuf:"bytes,3,opt,name=wantedItemsOwnerID,proto3" json:"wantedItemsOwnerID,omitempty"`
+	OfferedItems       []*ItemToTrade             `protobuf:"bytes,4,rep,name=offeredItems,proto3" json:"offeredItems,omitempty"`
+	WantedItems        []*ItemToTrade             `protobuf:"bytes,5,rep,name=wantedItems,proto3" json:"wantedItems,omitempty"`
+	Participants       []*ParticipantItemsToTrade `protobuf:"bytes,6,rep,name=participants,proto3" json:"participants,omitempty"`
+	OfferedCurrency    int64                      `protobuf:"varint,7,opt,name=offeredCurrency,proto3" json:"offeredCurrency,omitempty"`
+	WantedCurrency     int64                      `protobuf:"varint,8,opt,name=wantedCurrency,proto3" json:"wantedCurrency,omitempty"`
 }
 
 func (x *TradeItemsRequest) Reset() {
 	*x = TradeItemsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[4]
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -275,7 +420,7 @@ func (x *TradeItemsRequest) String() string {
 func (*TradeItemsRequest) ProtoMessage() {}
 
 func (x *TradeItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[4]
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +433,7 @@ func (x *TradeItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TradeItemsRequest.ProtoReflect.Descriptor instead.
 func (*TradeItemsRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{4}
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *TradeItemsRequest) GetTradeID() string {
@@ -326,6 +471,98 @@ func (x *TradeItemsRequest) GetWantedItems() []*ItemToTrade {
 	return nil
 }
 
+func (x *TradeItemsRequest) GetParticipants() []*ParticipantItemsToTrade {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *TradeItemsRequest) GetOfferedCurrency() int64 {
+	if x != nil {
+		return x.OfferedCurrency
+	}
+	return 0
+}
+
+func (x *TradeItemsRequest) GetWantedCurrency() int64 {
+	if x != nil {
+		return x.WantedCurrency
+	}
+	return 0
+}
+
+type UnlockItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LockedBy string        `protobuf:"bytes,1,opt,name=lockedBy,proto3" json:"lockedBy,omitempty"`
+	OwnerID  string        `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	Items    []*ItemToLock `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Currency int64         `protobuf:"varint,4,opt,name=currency,proto3" json:"currency,omitempty"`
+}
+
+func (x *UnlockItemsRequest) Reset() {
+	*x = UnlockItemsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnlockItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockItemsRequest) ProtoMessage() {}
+
+func (x *UnlockItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_trades_external_inventory_proto_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockItemsRequest.ProtoReflect.Descriptor instead.
+func (*UnlockItemsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UnlockItemsRequest) GetLockedBy() string {
+	if x != nil {
+		return x.LockedBy
+	}
+	return ""
+}
+
+func (x *UnlockItemsRequest) GetOwnerID() string {
+	if x != nil {
+		return x.OwnerID
+	}
+	return ""
+}
+
+func (x *UnlockItemsRequest) GetItems() []*ItemToLock {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *UnlockItemsRequest) GetCurrency() int64 {
+	if x != nil {
+		return x.Currency
+	}
+	return 0
+}
+
 var File_pkg_trades_external_inventory_proto_service_proto protoreflect.FileDescriptor
 
 var file_pkg_trades_external_inventory_proto_service_proto_rawDesc = []byte{
@@ -337,52 +574,102 @@ var file_pkg_trades_external_inventory_proto_service_proto_rawDesc = []byte{
 	0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
 	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x22, 0xec, 0x01, 0x0a, 0x10, 0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64,
-	0x42, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64,
-	0x42, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2e, 0x0a, 0x12,
-	0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64,
-	0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x39, 0x0a, 0x0c,
-	0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49,
-	0x74, 0x65, 0x6d, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x6f, 0x66, 0x66, 0x65, 0x72,
-	0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x37, 0x0a, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65,
-	0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69,
-	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x4c,
-	0x6f, 0x63, 0x6b, 0x52, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x22, 0x39, 0x0a, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0xed, 0x01, 0x0a, 0x11,
-	0x54, 0x72, 0x61, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f,
-	0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77,
-	0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2e, 0x0a, 0x12, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49,
-	0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x12, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x3a, 0x0a, 0x0c, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64,
-	0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x69, 0x6e,
-	0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72,
-	0x61, 0x64, 0x65, 0x52, 0x0c, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d,
-	0x73, 0x12, 0x38, 0x0a, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
-	0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x0b,
-	0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x32, 0x90, 0x01, 0x0a, 0x10,
-	0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x12, 0x3c, 0x0a, 0x09, 0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1b, 0x2e,
-	0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74,
-	0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x69, 0x6e, 0x76,
-	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3e,
-	0x0a, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1c, 0x2e, 0x69,
-	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x49, 0x74,
-	0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x69, 0x6e, 0x76,
-	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x25,
-	0x5a, 0x23, 0x70, 0x6b, 0x67, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2f, 0x65, 0x78, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x79, 0x22, 0x5f, 0x0a, 0x16, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x49, 0x74, 0x65, 0x6d, 0x73, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6f,
+	0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2b, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x22, 0x85, 0x03, 0x0a, 0x10, 0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x64, 0x42, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x64, 0x42, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2e, 0x0a,
+	0x12, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x77, 0x61, 0x6e, 0x74, 0x65,
+	0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x39, 0x0a,
+	0x0c, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e,
+	0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x37, 0x0a, 0x0b, 0x77, 0x61, 0x6e, 0x74,
+	0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f,
+	0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x12, 0x45, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x77, 0x61, 0x6e, 0x74,
+	0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x39, 0x0a, 0x0b, 0x49, 0x74,
+	0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0xa9, 0x01, 0x0a, 0x17, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x36, 0x0a, 0x0a, 0x67,
+	0x69, 0x76, 0x65, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d,
+	0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x0a, 0x67, 0x69, 0x76, 0x65, 0x6e, 0x49, 0x74,
+	0x65, 0x6d, 0x73, 0x12, 0x3c, 0x0a, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x69, 0x6e, 0x76,
+	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72, 0x61,
+	0x64, 0x65, 0x52, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x22, 0x87, 0x03, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x64, 0x65,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x64, 0x65, 0x49,
+	0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2e, 0x0a, 0x12, 0x77,
+	0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49,
+	0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x3a, 0x0a, 0x0c, 0x6f,
+	0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74,
+	0x65, 0x6d, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x0c, 0x6f, 0x66, 0x66, 0x65, 0x72,
+	0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x38, 0x0a, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65,
+	0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x69,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x52, 0x0b, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x12, 0x46, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x54, 0x6f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x0c, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6f, 0x66, 0x66,
+	0x65, 0x72, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x77, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x43, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x77, 0x61, 0x6e,
+	0x74, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x93, 0x01, 0x0a, 0x12,
+	0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x42, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x42, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2b, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x52, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x32, 0xd2, 0x01, 0x0a, 0x10, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74,
+	0x65, 0x6d, 0x73, 0x12, 0x1b, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e,
+	0x4c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x49, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x1c, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0b, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74,
+	0x65, 0x6d, 0x73, 0x12, 0x1d, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e,
+	0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x25, 0x5a, 0x23, 0x70, 0x6b, 0x67, 0x2f, 0x74, 0x72,
+	0x61, 0x64, 0x65, 0x73, 0x2f, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x69, 0x6e,
+	0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -397,28 +684,39 @@ func file_pkg_trades_external_inventory_proto_service_proto_rawDescGZIP() []byte
 	return file_pkg_trades_external_inventory_proto_service_proto_rawDescData
 }
 
-var file_pkg_trades_external_inventory_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pkg_trades_external_inventory_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_pkg_trades_external_inventory_proto_service_proto_goTypes = []interface{}{
-	(*Empty)(nil),             // 0: inventory.Empty
-	(*ItemToLock)(nil),        // 1: inventory.ItemToLock
-	(*LockItemsRequest)(nil),  // 2: inventory.LockItemsRequest
-	(*ItemToTrade)(nil),       // 3: inventory.ItemToTrade
-	(*TradeItemsRequest)(nil), // 4: inventory.TradeItemsRequest
+	(*Empty)(nil),                   // 0: inventory.Empty
+	(*ItemToLock)(nil),              // 1: inventory.ItemToLock
+	(*ParticipantItemsToLock)(nil),  // 2: inventory.ParticipantItemsToLock
+	(*LockItemsRequest)(nil),        // 3: inventory.LockItemsRequest
+	(*ItemToTrade)(nil),             // 4: inventory.ItemToTrade
+	(*ParticipantItemsToTrade)(nil), // 5: inventory.ParticipantItemsToTrade
+	(*TradeItemsRequest)(nil),       // 6: inventory.TradeItemsRequest
+	(*UnlockItemsRequest)(nil),      // 7: inventory.UnlockItemsRequest
 }
 var file_pkg_trades_external_inventory_proto_service_proto_depIdxs = []int32{
-	1, // 0: inventory.LockItemsRequest.offeredItems:type_name -> inventory.ItemToLock
-	1, // 1: inventory.LockItemsRequest.wantedItems:type_name -> inventory.ItemToLock
-	3, // 2: inventory.TradeItemsRequest.offeredItems:type_name -> inventory.ItemToTrade
-	3, // 3: inventory.TradeItemsRequest.wantedItems:type_name -> inventory.ItemToTrade
-	2, // 4: inventory.InventoryService.LockItems:input_type -> inventory.LockItemsRequest
-	4, // 5: inventory.InventoryService.TradeItems:input_type -> inventory.TradeItemsRequest
-	0, // 6: inventory.InventoryService.LockItems:output_type -> inventory.Empty
-	0, // 7: inventory.InventoryService.TradeItems:output_type -> inventory.Empty
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	1,  // 0: inventory.ParticipantItemsToLock.items:type_name -> inventory.ItemToLock
+	1,  // 1: inventory.LockItemsRequest.offeredItems:type_name -> inventory.ItemToLock
+	1,  // 2: inventory.LockItemsRequest.wantedItems:type_name -> inventory.ItemToLock
+	2,  // 3: inventory.LockItemsRequest.participants:type_name -> inventory.ParticipantItemsToLock
+	4,  // 4: inventory.ParticipantItemsToTrade.givenItems:type_name -> inventory.ItemToTrade
+	4,  // 5: inventory.ParticipantItemsToTrade.receivedItems:type_name -> inventory.ItemToTrade
+	4,  // 6: inventory.TradeItemsRequest.offeredItems:type_name -> inventory.ItemToTrade
+	4,  // 7: inventory.TradeItemsRequest.wantedItems:type_name -> inventory.ItemToTrade
+	5,  // 8: inventory.TradeItemsRequest.participants:type_name -> inventory.ParticipantItemsToTrade
+	1,  // 9: inventory.UnlockItemsRequest.items:type_name -> inventory.ItemToLock
+	3,  // 10: inventory.InventoryService.LockItems:input_type -> inventory.LockItemsRequest
+	6,  // 11: inventory.InventoryService.TradeItems:input_type -> inventory.TradeItemsRequest
+	7,  // 12: inventory.InventoryService.UnlockItems:input_type -> inventory.UnlockItemsRequest
+	0,  // 13: inventory.InventoryService.LockItems:output_type -> inventory.Empty
+	0,  // 14: inventory.InventoryService.TradeItems:output_type -> inventory.Empty
+	0,  // 15: inventory.InventoryService.UnlockItems:output_type -> inventory.Empty
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_pkg_trades_external_inventory_proto_service_proto_init() }
@@ -452,7 +750,7 @@ func file_pkg_trades_external_inventory_proto_service_proto_init() {
 			}
 		}
 		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LockItemsRequest); i {
+			switch v := v.(*ParticipantItemsToLock); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -464,7 +762,7 @@ func file_pkg_trades_external_inventory_proto_service_proto_init() {
 			}
 		}
 		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ItemToTrade); i {
+			switch v := v.(*LockItemsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -476,6 +774,30 @@ func file_pkg_trades_external_inventory_proto_service_proto_init() {
 			}
 		}
 		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ItemToTrade); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParticipantItemsToTrade); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TradeItemsRequest); i {
 			case 0:
 				return &v.state
@@ -487,6 +809,18 @@ func file_pkg_trades_external_inventory_proto_service_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_trades_external_inventory_proto_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnlockItemsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -494,7 +828,7 @@ func file_pkg_trades_external_inventory_proto_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_trades_external_inventory_proto_service_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
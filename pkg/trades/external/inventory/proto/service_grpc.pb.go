@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion7
 type InventoryServiceClient interface {
 	LockItems(ctx context.Context, in *LockItemsRequest, opts ...grpc.CallOption) (*Empty, error)
 	TradeItems(ctx context.Context, in *TradeItemsRequest, opts ...grpc.CallOption) (*Empty, error)
+	UnlockItems(ctx context.Context, in *UnlockItemsRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type inventoryServiceClient struct {
@@ -48,12 +49,22 @@ func (c *inventoryServiceClient) TradeItems(ctx context.Context, in *TradeItemsR
 	return out, nil
 }
 
+func (c *inventoryServiceClient) UnlockItems(ctx context.Context, in *UnlockItemsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/inventory.InventoryService/UnlockItems", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InventoryServiceServer is the server API for InventoryService service.
 // All implementations must embed UnimplementedInventoryServiceServer
 // for forward compatibility
 type InventoryServiceServer interface {
 	LockItems(context.Context, *LockItemsRequest) (*Empty, error)
 	TradeItems(context.Context, *TradeItemsRequest) (*Empty, error)
+	UnlockItems(context.Context, *UnlockItemsRequest) (*Empty, error)
 	mustEmbedUnimplementedInventoryServiceServer()
 }
 
@@ -67,6 +78,9 @@ func (UnimplementedInventoryServiceServer) LockItems(context.Context, *LockItems
 func (UnimplementedInventoryServiceServer) TradeItems(context.Context, *TradeItemsRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TradeItems not implemented")
 }
+func (UnimplementedInventoryServiceServer) UnlockItems(context.Context, *UnlockItemsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockItems not implemented")
+}
 func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
 
 // UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -116,6 +130,24 @@ func _InventoryService_TradeItems_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InventoryService_UnlockItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).UnlockItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inventory.InventoryService/UnlockItems",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).UnlockItems(ctx, req.(*UnlockItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -131,6 +163,10 @@ var InventoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TradeItems",
 			Handler:    _InventoryService_TradeItems_Handler,
 		},
+		{
+			MethodName: "UnlockItems",
+			Handler:    _InventoryService_UnlockItems_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/trades/external/inventory/proto/service.proto",
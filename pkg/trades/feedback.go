@@ -0,0 +1,99 @@
+package trades
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/google/uuid"
+)
+
+const (
+	// MinFeedbackRating ...
+	MinFeedbackRating = 1
+
+	// MaxFeedbackRating ...
+	MaxFeedbackRating = 5
+
+	// MaxFeedbackCommentLength counted in characters
+	MaxFeedbackCommentLength = 1000
+)
+
+// Feedback left by a participant of a completed trade about the other one
+type Feedback struct {
+	ID          string    `bson:"_id"`
+	TradeID     string    `bson:"trade_id"`
+	AuthorID    string    `bson:"author_id"`
+	RecipientID string    `bson:"recipient_id"`
+	Rating      int       `bson:"rating"`
+	Comment     string    `bson:"comment"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// ResultFeedback ...
+type ResultFeedback struct {
+	Feedback []*Feedback
+	Token    string
+}
+
+// FeedbackSummary aggregate of every feedback a user received
+type FeedbackSummary struct {
+	Count   int64
+	Average float64
+	// Ratings number of feedback per rating
+	Ratings map[int]int64
+}
+
+// FeedbackRepository ...
+type FeedbackRepository interface {
+	// Insert returns core.ErrFeedbackAlreadySubmitted when the author already rated the trade
+	Insert(ctx context.Context, feedback *Feedback) error
+	Get(ctx context.Context, recipientID string, req *GetTradesOffers) (*ResultFeedback, error)
+	Summary(ctx context.Context, recipientID string) (*FeedbackSummary, error)
+}
+
+// FeedbackService ...
+type FeedbackService interface {
+	Create(ctx context.Context, userID, correlationID, tradeID string, req *CreateFeedbackRequest) (*CreateFeedbackResponse, error)
+	Get(ctx context.Context, recipientID string, req *GetTradeOffersRequest) (*GetFeedbackResponse, error)
+	GetSummary(ctx context.Context, recipientID string) (*GetFeedbackSummaryResponse, error)
+}
+
+// NewFeedback validates the rating and comment of a new feedback
+func NewFeedback(trade *TradeOffer, authorID string, rating int, comment string) (*Feedback, error) {
+
+	validation := core.NewValidation()
+
+	if rating < MinFeedbackRating {
+		validation.Add("rating", core.RuleMin, fmt.Sprintf("must be at least %d", MinFeedbackRating))
+	}
+
+	if rating > MaxFeedbackRating {
+		validation.Add("rating", core.RuleMax, fmt.Sprintf("must be at most %d", MaxFeedbackRating))
+	}
+
+	if utf8.RuneCountInString(comment) > MaxFeedbackCommentLength {
+		validation.Add("comment", core.RuleMax, fmt.Sprintf("must have at most %d characters", MaxFeedbackCommentLength))
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	recipientID := trade.WantedItemsOwnerID
+	if authorID == trade.WantedItemsOwnerID {
+		recipientID = trade.OwnerID
+	}
+
+	return &Feedback{
+		ID:          uuid.NewString(),
+		TradeID:     trade.ID,
+		AuthorID:    authorID,
+		RecipientID: recipientID,
+		Rating:      rating,
+		Comment:     comment,
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// FeedbackController ...
+type FeedbackController struct {
+	authenticate *core.Authenticate
+	service      FeedbackService
+}
+
+// NewFeedbackController ...
+func NewFeedbackController(authenticate *core.Authenticate, service FeedbackService) FeedbackController {
+	return FeedbackController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *FeedbackController) RegisterRoutes(r *gin.RouterGroup) {
+	feedback := r.Group("/trades/feedback")
+	{
+		feedback.Use(
+			c.authenticate.Middleware(),
+		)
+
+		feedback.POST(":id", c.post)
+	}
+
+	users := r.Group("/users")
+	{
+		users.Use(
+			c.authenticate.Middleware(),
+		)
+
+		users.GET(":id/feedback", c.get)
+		users.GET(":id/feedback/summary", c.getSummary)
+	}
+}
+
+func (c *FeedbackController) post(ctx *gin.Context) {
+	req := new(CreateFeedbackRequest)
+	userID := ctx.GetString("user_id")
+	correlationID := ctx.GetString("X-Correlation-ID")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Create(ctx, userID, correlationID, ctx.Param("id"), req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *FeedbackController) get(ctx *gin.Context) {
+	req := new(GetTradeOffersRequest)
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, ctx.Param("id"), req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *FeedbackController) getSummary(ctx *gin.Context) {
+	res, err := c.service.GetSummary(ctx, ctx.Param("id"))
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
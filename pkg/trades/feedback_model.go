@@ -0,0 +1,85 @@
+package trades
+
+import (
+	"strconv"
+	"time"
+)
+
+// CreateFeedbackRequest ...
+type CreateFeedbackRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// CreateFeedbackResponse ...
+type CreateFeedbackResponse struct {
+	ID string `json:"id"`
+}
+
+// FeedbackModel ...
+type FeedbackModel struct {
+	ID          string    `json:"id"`
+	TradeID     string    `json:"trade_id"`
+	AuthorID    string    `json:"author_id"`
+	RecipientID string    `json:"recipient_id"`
+	Rating      int       `json:"rating"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetFeedbackResponse ...
+type GetFeedbackResponse struct {
+	Feedback []*FeedbackModel `json:"feedback"`
+	Token    string           `json:"token"`
+}
+
+// GetFeedbackSummaryResponse ...
+type GetFeedbackSummaryResponse struct {
+	UserID  string           `json:"user_id"`
+	Count   int64            `json:"count"`
+	Average float64          `json:"average"`
+	Ratings map[string]int64 `json:"ratings"`
+}
+
+// ParseFeedback ...
+func ParseFeedback(feedback *Feedback) *FeedbackModel {
+	return &FeedbackModel{
+		ID:          feedback.ID,
+		TradeID:     feedback.TradeID,
+		AuthorID:    feedback.AuthorID,
+		RecipientID: feedback.RecipientID,
+		Rating:      feedback.Rating,
+		Comment:     feedback.Comment,
+		CreatedAt:   feedback.CreatedAt,
+	}
+}
+
+// ParseGetFeedbackResponse ...
+func ParseGetFeedbackResponse(res *ResultFeedback) *GetFeedbackResponse {
+	feedback := make([]*FeedbackModel, len(res.Feedback))
+
+	for i, f := range res.Feedback {
+		feedback[i] = ParseFeedback(f)
+	}
+
+	return &GetFeedbackResponse{
+		Token:    res.Token,
+		Feedback: feedback,
+	}
+}
+
+// ParseGetFeedbackSummaryResponse every rating is present even without feedback
+func ParseGetFeedbackSummaryResponse(userID string, summary *FeedbackSummary) *GetFeedbackSummaryResponse {
+	ratings := make(map[string]int64, MaxFeedbackRating)
+
+	for rating := MinFeedbackRating; rating <= MaxFeedbackRating; rating++ {
+		ratings[strconv.Itoa(rating)] = summary.Ratings[rating]
+	}
+
+	return &GetFeedbackSummaryResponse{
+		UserID:  userID,
+		Count:   summary.Count,
+		Average: summary.Average,
+		Ratings: ratings,
+	}
+}
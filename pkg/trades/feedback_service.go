@@ -0,0 +1,97 @@
+package trades
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+)
+
+type feedbackService struct {
+	repository      FeedbackRepository
+	tradeRepository Repository
+}
+
+// NewFeedbackService ...
+func NewFeedbackService(repository FeedbackRepository, tradeRepository Repository) FeedbackService {
+	return &feedbackService{
+		repository:      repository,
+		tradeRepository: tradeRepository,
+	}
+}
+
+func (s *feedbackService) Create(
+	ctx context.Context,
+	userID, correlationID, tradeID string,
+	req *CreateFeedbackRequest,
+) (*CreateFeedbackResponse, error) {
+
+	fields := logrus.Fields{
+		"user_id":        userID,
+		"trade_id":       tradeID,
+		"correlation_id": correlationID,
+	}
+
+	trade, err := s.tradeRepository.GetByID(ctx, userID, tradeID)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trade")
+		return nil, err
+	}
+
+	if trade.OwnerID != userID && trade.WantedItemsOwnerID != userID {
+		logrus.WithError(core.ErrForbidden).WithFields(fields).Error("user is not a participant of the trade")
+		return nil, core.ErrForbidden
+	}
+
+	if trade.Status != TradeCompleted {
+		logrus.WithError(core.ErrTradeInvalidStatus).WithFields(fields).Error("trade is not completed")
+		return nil, core.ErrTradeInvalidStatus
+	}
+
+	feedback, err := NewFeedback(trade, userID, req.Rating, req.Comment)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("invalid feedback")
+		return nil, err
+	}
+
+	if err := s.repository.Insert(ctx, feedback); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error inserting feedback")
+		return nil, err
+	}
+
+	logrus.WithFields(fields).WithField("feedback_id", feedback.ID).Info("feedback created")
+
+	return &CreateFeedbackResponse{ID: feedback.ID}, nil
+}
+
+func (s *feedbackService) Get(ctx context.Context, recipientID string, req *GetTradeOffersRequest) (*GetFeedbackResponse, error) {
+
+	res, err := s.repository.Get(ctx, recipientID, &GetTradesOffers{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	})
+
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"recipient_id": recipientID,
+				"token":        req.Token,
+			}).
+			Error("error getting feedback")
+		return nil, err
+	}
+
+	return ParseGetFeedbackResponse(res), nil
+}
+
+func (s *feedbackService) GetSummary(ctx context.Context, recipientID string) (*GetFeedbackSummaryResponse, error) {
+
+	summary, err := s.repository.Summary(ctx, recipientID)
+	if err != nil {
+		logrus.WithError(err).WithField("recipient_id", recipientID).Error("error getting feedback summary")
+		return nil, err
+	}
+
+	return ParseGetFeedbackSummaryResponse(recipientID, summary), nil
+}
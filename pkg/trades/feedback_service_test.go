@@ -0,0 +1,144 @@
+package trades_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type feedbackServiceTestSuite struct {
+	suite.Suite
+	assert          *assert.Assertions
+	ctx             context.Context
+	repository      *mock.FeedbackRepositoryMock
+	tradeRepository *mock.RepositoryMock
+	service         trades.FeedbackService
+}
+
+func TestFeedbackServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(feedbackServiceTestSuite))
+}
+
+func (s *feedbackServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *feedbackServiceTestSuite) SetupTest() {
+	s.repository = mock.NewFeedbackRepository().(*mock.FeedbackRepositoryMock)
+	s.tradeRepository = mock.NewRepository().(*mock.RepositoryMock)
+	s.service = trades.NewFeedbackService(s.repository, s.tradeRepository)
+}
+
+func (s *feedbackServiceTestSuite) TestCreate() {
+	trade := newCompletedTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Insert", trade.WantedItemsOwnerID).Return(nil)
+
+	res, err := s.service.Create(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID, &trades.CreateFeedbackRequest{
+		Rating:  5,
+		Comment: "fast and friendly",
+	})
+
+	s.assert.NoError(err)
+	s.assert.NotEmpty(res.ID)
+
+	feedback := s.repository.Calls[0].Arguments
+	s.assert.Equal(trade.WantedItemsOwnerID, feedback.String(0))
+}
+
+func (s *feedbackServiceTestSuite) TestCreateRecipientIsTheOtherParticipant() {
+	trade := newCompletedTrade()
+
+	feedback, err := trades.NewFeedback(trade, trade.OwnerID, 4, "")
+
+	s.assert.NoError(err)
+	s.assert.Equal(trade.WantedItemsOwnerID, feedback.RecipientID)
+}
+
+func (s *feedbackServiceTestSuite) TestCreateNotParticipant() {
+	trade := newCompletedTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+
+	res, err := s.service.Create(s.ctx, uuid.NewString(), uuid.NewString(), trade.ID, &trades.CreateFeedbackRequest{Rating: 1})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrForbidden)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *feedbackServiceTestSuite) TestCreateTradeNotCompleted() {
+	trade := newPendingTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+
+	res, err := s.service.Create(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, &trades.CreateFeedbackRequest{Rating: 3})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrTradeInvalidStatus)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *feedbackServiceTestSuite) TestCreateInvalid() {
+	trade := newCompletedTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+
+	res, err := s.service.Create(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, &trades.CreateFeedbackRequest{
+		Rating:  6,
+		Comment: strings.Repeat("a", trades.MaxFeedbackCommentLength+1),
+	})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+	s.assert.Len(err.(*core.Error).Violations, 2)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *feedbackServiceTestSuite) TestCreateAlreadySubmitted() {
+	trade := newCompletedTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Insert", trade.OwnerID).Return(core.ErrFeedbackAlreadySubmitted)
+
+	res, err := s.service.Create(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, &trades.CreateFeedbackRequest{Rating: 2})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrFeedbackAlreadySubmitted)
+}
+
+func (s *feedbackServiceTestSuite) TestGetSummary() {
+	userID := uuid.NewString()
+
+	s.repository.On("Summary", userID).Return(&trades.FeedbackSummary{
+		Count:   3,
+		Average: 4,
+		Ratings: map[int]int64{3: 1, 4: 1, 5: 1},
+	}, nil)
+
+	res, err := s.service.GetSummary(s.ctx, userID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(int64(3), res.Count)
+	s.assert.Equal(float64(4), res.Average)
+	s.assert.Equal(map[string]int64{"1": 0, "2": 0, "3": 1, "4": 1, "5": 1}, res.Ratings)
+}
+
+func newCompletedTrade() *trades.TradeOffer {
+	trade := newPendingTrade()
+	trade.Status = trades.TradeCompleted
+
+	return trade
+}
@@ -0,0 +1,124 @@
+package trades
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
+)
+
+const (
+	// ItemChangedSubscriberID queue consuming the inventory item changed events
+	ItemChangedSubscriberID = "trades-inventory-item-changed"
+
+	// ItemRemovedSubscriberID queue consuming the inventory item removed events
+	ItemRemovedSubscriberID = "trades-inventory-item-removed"
+)
+
+// InventoryConsumer invalidates the open offers that can no longer be traded
+// after their items change in the inventory
+type InventoryConsumer struct {
+	service  Service
+	topics   *core.Topics
+	registry *core.EventRegistry
+	inbox    *core.Inbox
+}
+
+// InventoryConsumerOption ...
+type InventoryConsumerOption func(*InventoryConsumer)
+
+// WithInventoryInbox skips the events the subscribers already processed, with
+// a transactional inbox InvalidateItem runs again on every transaction retry
+// and repeats the unlocks of the offers it closes
+func WithInventoryInbox(inbox *core.Inbox) InventoryConsumerOption {
+	return func(c *InventoryConsumer) {
+		c.inbox = inbox
+	}
+}
+
+// NewInventoryConsumer events are validated and upcast with the registry
+func NewInventoryConsumer(service Service, topics *core.Topics, registry *core.EventRegistry, opts ...InventoryConsumerOption) *InventoryConsumer {
+	if topics == nil {
+		topics = &core.Topics{}
+	}
+
+	consumer := &InventoryConsumer{service: service, topics: topics, registry: registry}
+
+	for _, opt := range opts {
+		opt(consumer)
+	}
+
+	return consumer
+}
+
+// Subscriptions returns a subscription for every configured topic
+func (c *InventoryConsumer) Subscriptions() []*core.Subscription {
+	subscriptions := []*core.Subscription{}
+
+	if c.topics.InventoryItemChanged != "" {
+		subscriptions = append(subscriptions, c.subscription(ItemChangedSubscriberID, c.topics.InventoryItemChanged, c.HandleItemChanged))
+	}
+
+	if c.topics.InventoryItemRemoved != "" {
+		subscriptions = append(subscriptions, c.subscription(ItemRemovedSubscriberID, c.topics.InventoryItemRemoved, c.HandleItemRemoved))
+	}
+
+	return subscriptions
+}
+
+// HandleItemChanged ...
+func (c *InventoryConsumer) HandleItemChanged(ctx context.Context, message *core.Message) error {
+	event := new(inventory.ItemChangedEvent)
+	if err := decodeEvent(message, inventory.ItemChangedEventType, event); err != nil {
+		return err
+	}
+
+	return c.service.InvalidateItem(ctx, message.CorrelationID, &ItemChange{
+		ItemID:   event.ID,
+		OwnerID:  event.OwnerID,
+		Quantity: event.Quantity,
+	})
+}
+
+// HandleItemRemoved ...
+func (c *InventoryConsumer) HandleItemRemoved(ctx context.Context, message *core.Message) error {
+	event := new(inventory.ItemRemovedEvent)
+	if err := decodeEvent(message, inventory.ItemRemovedEventType, event); err != nil {
+		return err
+	}
+
+	return c.service.InvalidateItem(ctx, message.CorrelationID, &ItemChange{
+		ItemID:  event.ID,
+		OwnerID: event.OwnerID,
+		Removed: true,
+	})
+}
+
+func (c *InventoryConsumer) subscription(subscriberID, topicID string, handler core.HandlerFunc) *core.Subscription {
+	middlewares := []core.Middleware{
+		core.HandlerLog(subscriberID),
+		core.HandlerEnvelope(c.registry),
+		core.HandlerCorrelationID(),
+	}
+
+	if c.inbox != nil {
+		middlewares = append(middlewares, core.HandlerInbox(subscriberID, c.inbox))
+	}
+
+	return &core.Subscription{
+		SubscriberID: subscriberID,
+		TopicID:      topicID,
+		Handler:      handler,
+		Middlewares:  middlewares,
+	}
+}
+
+// decodeEvent rejects envelopes of another type published to the topic
+func decodeEvent(message *core.Message, eventType string, event interface{}) error {
+	if message.Envelope == nil || message.Envelope.Type != eventType {
+		return fmt.Errorf("%w: expected %s event", core.ErrInvalidEvent, eventType)
+	}
+
+	return message.Decode(event)
+}
@@ -0,0 +1,102 @@
+package trades_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type inventoryConsumerTestSuite struct {
+	suite.Suite
+	assert   *assert.Assertions
+	ctx      context.Context
+	service  *mock.TradeServiceMock
+	registry *core.EventRegistry
+	consumer *trades.InventoryConsumer
+}
+
+func TestInventoryConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(inventoryConsumerTestSuite))
+}
+
+func (s *inventoryConsumerTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+
+	registry, err := core.NewEventRegistry(trades.EventSchemas())
+	s.Require().NoError(err)
+
+	s.registry = registry
+}
+
+func (s *inventoryConsumerTestSuite) SetupTest() {
+	s.service = mock.NewTradeService().(*mock.TradeServiceMock)
+	s.consumer = trades.NewInventoryConsumer(s.service, &core.Topics{
+		InventoryItemChanged: "inventory-item-changed",
+		InventoryItemRemoved: "inventory-item-removed",
+	}, s.registry)
+}
+
+func (s *inventoryConsumerTestSuite) TestItemRemoved() {
+	itemID := uuid.NewString()
+
+	s.service.On("InvalidateItem", itemID).Return(nil)
+
+	err := s.handle(trades.ItemRemovedSubscriberID, inventory.ItemRemovedEventType, &inventory.ItemRemovedEvent{
+		ID:      itemID,
+		OwnerID: uuid.NewString(),
+	})
+
+	s.assert.NoError(err)
+	s.service.AssertNumberOfCalls(s.T(), "InvalidateItem", 1)
+}
+
+func (s *inventoryConsumerTestSuite) TestItemChangedWrongType() {
+	err := s.handle(trades.ItemChangedSubscriberID, inventory.ItemRemovedEventType, &inventory.ItemRemovedEvent{
+		ID:      uuid.NewString(),
+		OwnerID: uuid.NewString(),
+	})
+
+	s.assert.ErrorIs(err, core.ErrMalformedMessage)
+	s.service.AssertNumberOfCalls(s.T(), "InvalidateItem", 0)
+}
+
+func (s *inventoryConsumerTestSuite) TestTradeOfferUpdatedSchema() {
+	_, err := s.registry.NewEnvelope(trades.TradeOfferUpdatedEventType, uuid.NewString(), "tests", &trades.TradeOfferUpdatedEvent{
+		TradeID:            uuid.NewString(),
+		OwnerID:            uuid.NewString(),
+		WantedItemsOwnerID: uuid.NewString(),
+		Revision:           2,
+		UpdatedAt:          time.Now(),
+	})
+
+	s.assert.NoError(err)
+}
+
+// handle runs the message through the middlewares of the subscription like a subscriber does
+func (s *inventoryConsumerTestSuite) handle(subscriberID, eventType string, event interface{}) error {
+	envelope, err := s.registry.NewEnvelope(eventType, uuid.NewString(), "inventory", event)
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(envelope)
+	s.Require().NoError(err)
+
+	for _, subscription := range s.consumer.Subscriptions() {
+		if subscription.SubscriberID == subscriberID {
+			handler := core.Chain(subscription.Handler, subscription.Middlewares...)
+			return handler.Handle(s.ctx, &core.Message{ID: uuid.NewString(), Body: body})
+		}
+	}
+
+	s.FailNow("unknown subscriber " + subscriberID)
+	return nil
+}
@@ -0,0 +1,284 @@
+package trades
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WantList items a user has available and items the user wants from others
+type WantList struct {
+	UserID string
+	Haves  []*Item
+	Wants  []*Item
+}
+
+// Suggestion trade proposed by the matching engine, participants are
+// stored the same way as in a multi-party trade
+type Suggestion struct {
+	ID           string         `bson:"_id"`
+	Generation   string         `bson:"generation"`
+	UserIDs      []string       `bson:"user_ids"`
+	Participants []*Participant `bson:"participants"`
+	CreatedAt    time.Time      `bson:"created_at"`
+}
+
+// ResultSuggestions ...
+type ResultSuggestions struct {
+	Suggestions []*Suggestion
+	Token       string
+}
+
+// SuggestionRepository ...
+type SuggestionRepository interface {
+	// Replace stores a new generation of suggestions and removes the previous ones
+	Replace(ctx context.Context, generation string, suggestions []*Suggestion) error
+	Get(ctx context.Context, userID string, req *GetTradesOffers) (*ResultSuggestions, error)
+}
+
+// MatchingService ...
+type MatchingService interface {
+	// Run rebuilds every suggestion from the open trade offers
+	Run(ctx context.Context) error
+	Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetSuggestionsResponse, error)
+}
+
+// MatchingEngine finds users whose haves and wants complement each other
+type MatchingEngine struct {
+	cyclic                bool
+	maxSuggestionsPerUser int
+}
+
+// NewMatchingEngine creates an engine that finds mutual matches and, when
+// cyclic is set, three-way cycles. Zero max suggestions means unlimited
+func NewMatchingEngine(cyclic bool, maxSuggestionsPerUser int) *MatchingEngine {
+	return &MatchingEngine{
+		cyclic:                cyclic,
+		maxSuggestionsPerUser: maxSuggestionsPerUser,
+	}
+}
+
+// BuildWantLists derives the haves and wants of every owner from their offers
+func BuildWantLists(offers []*TradeOffer) []*WantList {
+	lists := map[string]*WantList{}
+	order := []string{}
+
+	for _, offer := range offers {
+		list, exists := lists[offer.OwnerID]
+		if !exists {
+			list = &WantList{UserID: offer.OwnerID}
+			lists[offer.OwnerID] = list
+			order = append(order, offer.OwnerID)
+		}
+
+		list.Haves = mergeItems(list.Haves, offer.OfferedItems)
+		list.Wants = mergeItems(list.Wants, offer.WantedItems)
+	}
+
+	result := make([]*WantList, len(order))
+	for i, userID := range order {
+		result[i] = lists[userID]
+	}
+
+	return result
+}
+
+type ownedItem struct {
+	userID   string
+	quantity int64
+}
+
+// Match returns the suggestions found between the want lists
+func (e *MatchingEngine) Match(lists []*WantList) []*Suggestion {
+
+	haves := map[string][]ownedItem{}
+
+	for _, list := range lists {
+		for _, item := range list.Haves {
+			haves[item.ID] = append(haves[item.ID], ownedItem{userID: list.UserID, quantity: item.Quantity})
+		}
+	}
+
+	// wants[a][b] are the items a wants that b has
+	wants := map[string]map[string][]*Item{}
+
+	for _, list := range lists {
+		for _, item := range list.Wants {
+			for _, owner := range haves[item.ID] {
+				if owner.userID == list.UserID {
+					continue
+				}
+
+				from, exists := wants[list.UserID]
+				if !exists {
+					from = map[string][]*Item{}
+					wants[list.UserID] = from
+				}
+
+				quantity := item.Quantity
+				if owner.quantity < quantity {
+					quantity = owner.quantity
+				}
+
+				from[owner.userID] = append(from[owner.userID], &Item{ID: item.ID, Quantity: quantity})
+			}
+		}
+	}
+
+	users := sortedUsers(wants)
+
+	// sorted once so every traversal is deterministic
+	owners := make(map[string][]string, len(wants))
+	for user, from := range wants {
+		owners[user] = sortedOwners(from)
+	}
+
+	counts := map[string]int{}
+	createdAt := time.Now()
+	suggestions := []*Suggestion{}
+
+	for _, a := range users {
+		for _, b := range owners[a] {
+			if b <= a {
+				continue
+			}
+
+			back, exists := wants[b][a]
+			if !exists || !e.reserve(counts, a, b) {
+				continue
+			}
+
+			suggestions = append(suggestions, newSuggestion(createdAt,
+				&Participant{UserID: a, GivenItems: back, ReceivedItems: wants[a][b]},
+				&Participant{UserID: b, GivenItems: wants[a][b], ReceivedItems: back},
+			))
+		}
+	}
+
+	if !e.cyclic {
+		return suggestions
+	}
+
+	// a receives from b, b receives from c and c receives from a; a is always
+	// the smallest id so every cycle is found once per direction
+	for _, a := range users {
+		for _, b := range owners[a] {
+			if b <= a {
+				continue
+			}
+
+			for _, c := range owners[b] {
+				if c <= a || c == b {
+					continue
+				}
+
+				fromA, exists := wants[c][a]
+				if !exists || !e.reserve(counts, a, b, c) {
+					continue
+				}
+
+				fromB, fromC := wants[a][b], wants[b][c]
+
+				suggestions = append(suggestions, newSuggestion(createdAt,
+					&Participant{UserID: a, GivenItems: fromA, ReceivedItems: fromB},
+					&Participant{UserID: b, GivenItems: fromB, ReceivedItems: fromC},
+					&Participant{UserID: c, GivenItems: fromC, ReceivedItems: fromA},
+				))
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// Participant returns the participant with the given user id or nil
+func (suggestion *Suggestion) Participant(userID string) *Participant {
+	for _, participant := range suggestion.Participants {
+		if participant.UserID == userID {
+			return participant
+		}
+	}
+
+	return nil
+}
+
+func (e *MatchingEngine) reserve(counts map[string]int, users ...string) bool {
+	if e.maxSuggestionsPerUser < 1 {
+		return true
+	}
+
+	for _, user := range users {
+		if counts[user] >= e.maxSuggestionsPerUser {
+			return false
+		}
+	}
+
+	for _, user := range users {
+		counts[user]++
+	}
+
+	return true
+}
+
+func newSuggestion(createdAt time.Time, participants ...*Participant) *Suggestion {
+	userIDs := make([]string, len(participants))
+
+	for i, participant := range participants {
+		userIDs[i] = participant.UserID
+	}
+
+	return &Suggestion{
+		ID:           uuid.NewString(),
+		UserIDs:      userIDs,
+		Participants: participants,
+		CreatedAt:    createdAt,
+	}
+}
+
+// mergeItems adds the items to s keeping the greatest quantity of repeated ones
+func mergeItems(s []*Item, items []*Item) []*Item {
+	for _, item := range items {
+		merged := false
+
+		for _, existing := range s {
+			if existing.ID == item.ID {
+				if item.Quantity > existing.Quantity {
+					existing.Quantity = item.Quantity
+				}
+
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			s = append(s, &Item{ID: item.ID, Quantity: item.Quantity})
+		}
+	}
+
+	return s
+}
+
+func sortedUsers(wants map[string]map[string][]*Item) []string {
+	users := make([]string, 0, len(wants))
+	for user := range wants {
+		users = append(users, user)
+	}
+
+	sort.Strings(users)
+
+	return users
+}
+
+func sortedOwners(from map[string][]*Item) []string {
+	owners := make([]string, 0, len(from))
+	for owner := range from {
+		owners = append(owners, owner)
+	}
+
+	sort.Strings(owners)
+
+	return owners
+}
@@ -0,0 +1,53 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// MatchingController ...
+type MatchingController struct {
+	authenticate *core.Authenticate
+	service      MatchingService
+}
+
+// NewMatchingController ...
+func NewMatchingController(authenticate *core.Authenticate, service MatchingService) MatchingController {
+	return MatchingController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *MatchingController) RegisterRoutes(r *gin.RouterGroup) {
+	suggestions := r.Group("/trades/suggestions")
+	{
+		suggestions.Use(
+			c.authenticate.Middleware(),
+		)
+
+		suggestions.GET("", c.get)
+	}
+}
+
+func (c *MatchingController) get(ctx *gin.Context) {
+	req := new(GetTradeOffersRequest)
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, userID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
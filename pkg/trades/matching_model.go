@@ -0,0 +1,71 @@
+package trades
+
+import "time"
+
+// SuggestionModel bilateral suggestions are returned as an offer draft from
+// the point of view of the requesting user, cyclic ones as participants
+type SuggestionModel struct {
+	ID           string              `json:"id"`
+	Offer        *TradeOfferModel    `json:"offer,omitempty"`
+	Participants []*ParticipantModel `json:"participants,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// GetSuggestionsResponse ...
+type GetSuggestionsResponse struct {
+	Suggestions []*SuggestionModel `json:"suggestions"`
+	Token       string             `json:"token"`
+}
+
+// ParseSuggestion ...
+func ParseSuggestion(userID string, suggestion *Suggestion) *SuggestionModel {
+	model := &SuggestionModel{
+		ID:        suggestion.ID,
+		CreatedAt: suggestion.CreatedAt,
+	}
+
+	if len(suggestion.Participants) == 2 {
+		self := suggestion.Participant(userID)
+
+		var counterparty *Participant
+		for _, participant := range suggestion.Participants {
+			if participant.UserID != userID {
+				counterparty = participant
+			}
+		}
+
+		if self != nil && counterparty != nil {
+			model.Offer = &TradeOfferModel{
+				OwnerID:            self.UserID,
+				WantedItemsOwnerID: counterparty.UserID,
+				OfferedItems:       ParseItemSlice(self.GivenItems),
+				WantedItems:        ParseItemSlice(self.ReceivedItems),
+				CreatedAt:          suggestion.CreatedAt,
+			}
+
+			return model
+		}
+	}
+
+	model.Participants = make([]*ParticipantModel, len(suggestion.Participants))
+
+	for i, participant := range suggestion.Participants {
+		model.Participants[i] = ParseParticipant(participant)
+	}
+
+	return model
+}
+
+// ParseGetSuggestionsResponse ...
+func ParseGetSuggestionsResponse(userID string, res *ResultSuggestions) *GetSuggestionsResponse {
+	suggestions := make([]*SuggestionModel, len(res.Suggestions))
+
+	for i, suggestion := range res.Suggestions {
+		suggestions[i] = ParseSuggestion(userID, suggestion)
+	}
+
+	return &GetSuggestionsResponse{
+		Token:       res.Token,
+		Suggestions: suggestions,
+	}
+}
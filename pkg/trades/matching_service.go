@@ -0,0 +1,115 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MatchableTradeStatuses offers whose items take part in the matching
+var MatchableTradeStatuses = []TradeStatus{TradePending, TradeListed}
+
+type matchingService struct {
+	repository            Repository
+	suggestionsRepository SuggestionRepository
+	engine                *MatchingEngine
+	batchSize             int64
+}
+
+// NewMatchingService ...
+func NewMatchingService(
+	repository Repository,
+	suggestionsRepository SuggestionRepository,
+	settings *core.Matching,
+) MatchingService {
+
+	if settings == nil {
+		settings = &core.Matching{}
+	}
+
+	batchSize := settings.BatchSize
+	if batchSize < 1 {
+		batchSize = 500
+	}
+
+	return &matchingService{
+		repository:            repository,
+		suggestionsRepository: suggestionsRepository,
+		engine:                NewMatchingEngine(settings.Cyclic, settings.MaxSuggestionsPerUser),
+		batchSize:             batchSize,
+	}
+}
+
+func (s *matchingService) Run(ctx context.Context) error {
+
+	generation := uuid.NewString()
+	fields := logrus.Fields{"generation": generation}
+	started := time.Now()
+
+	offers := []*TradeOffer{}
+	req := &GetTradesOffers{PageSize: s.batchSize}
+
+	for {
+		res, err := s.repository.GetByStatus(ctx, MatchableTradeStatuses, req)
+		if err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error getting open trades")
+			return err
+		}
+
+		offers = append(offers, res.Trades...)
+
+		if int64(len(res.Trades)) < req.PageSize {
+			break
+		}
+
+		token := res.Token
+		req.Token = &token
+	}
+
+	lists := BuildWantLists(offers)
+	suggestions := s.engine.Match(lists)
+
+	for _, suggestion := range suggestions {
+		suggestion.Generation = generation
+	}
+
+	if err := s.suggestionsRepository.Replace(ctx, generation, suggestions); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error storing suggestions")
+		return err
+	}
+
+	logrus.
+		WithFields(fields).
+		WithFields(logrus.Fields{
+			"users":       len(lists),
+			"suggestions": len(suggestions),
+			"elapsed":     time.Since(started).String(),
+		}).
+		Info("suggestions rebuilt")
+
+	return nil
+}
+
+func (s *matchingService) Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetSuggestionsResponse, error) {
+
+	res, err := s.suggestionsRepository.Get(ctx, userID, &GetTradesOffers{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	})
+
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"user_id": userID,
+				"token":   req.Token,
+			}).
+			Error("error getting suggestions")
+		return nil, err
+	}
+
+	return ParseGetSuggestionsResponse(userID, res), nil
+}
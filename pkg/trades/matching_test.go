@@ -0,0 +1,197 @@
+package trades_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type matchingTestSuite struct {
+	suite.Suite
+	assert                *assert.Assertions
+	ctx                   context.Context
+	repository            *mock.RepositoryMock
+	suggestionsRepository *mock.SuggestionRepositoryMock
+	service               trades.MatchingService
+}
+
+func TestMatchingTestSuite(t *testing.T) {
+	suite.Run(t, new(matchingTestSuite))
+}
+
+func (s *matchingTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *matchingTestSuite) SetupTest() {
+	s.repository = mock.NewRepository().(*mock.RepositoryMock)
+	s.suggestionsRepository = mock.NewSuggestionRepository().(*mock.SuggestionRepositoryMock)
+	s.service = trades.NewMatchingService(s.repository, s.suggestionsRepository, &core.Matching{Cyclic: true})
+}
+
+func (s *matchingTestSuite) TestMatchMutual() {
+	lists := []*trades.WantList{
+		{
+			UserID: "a",
+			Haves:  []*trades.Item{{ID: "sword", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "shield", Quantity: 2}},
+		},
+		{
+			UserID: "b",
+			Haves:  []*trades.Item{{ID: "shield", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "sword", Quantity: 1}},
+		},
+	}
+
+	suggestions := trades.NewMatchingEngine(false, 0).Match(lists)
+
+	s.assert.Len(suggestions, 1)
+	s.assert.Equal([]string{"a", "b"}, suggestions[0].UserIDs)
+
+	a := suggestions[0].Participant("a")
+	s.assert.Equal("sword", a.GivenItems[0].ID)
+	s.assert.Equal("shield", a.ReceivedItems[0].ID)
+	s.assert.Equal(int64(1), a.ReceivedItems[0].Quantity)
+}
+
+func (s *matchingTestSuite) TestMatchCyclic() {
+	lists := []*trades.WantList{
+		{
+			UserID: "a",
+			Haves:  []*trades.Item{{ID: "sword", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "shield", Quantity: 1}},
+		},
+		{
+			UserID: "b",
+			Haves:  []*trades.Item{{ID: "shield", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "potion", Quantity: 1}},
+		},
+		{
+			UserID: "c",
+			Haves:  []*trades.Item{{ID: "potion", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "sword", Quantity: 1}},
+		},
+	}
+
+	s.assert.Empty(trades.NewMatchingEngine(false, 0).Match(lists))
+
+	suggestions := trades.NewMatchingEngine(true, 0).Match(lists)
+
+	s.assert.Len(suggestions, 1)
+	s.assert.Equal([]string{"a", "b", "c"}, suggestions[0].UserIDs)
+	s.assert.Equal("potion", suggestions[0].Participant("b").ReceivedItems[0].ID)
+}
+
+func (s *matchingTestSuite) TestMatchMaxSuggestionsPerUser() {
+	lists := []*trades.WantList{
+		{
+			UserID: "a",
+			Haves:  []*trades.Item{{ID: "sword", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "shield", Quantity: 1}, {ID: "potion", Quantity: 1}},
+		},
+		{
+			UserID: "b",
+			Haves:  []*trades.Item{{ID: "shield", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "sword", Quantity: 1}},
+		},
+		{
+			UserID: "c",
+			Haves:  []*trades.Item{{ID: "potion", Quantity: 1}},
+			Wants:  []*trades.Item{{ID: "sword", Quantity: 1}},
+		},
+	}
+
+	s.assert.Len(trades.NewMatchingEngine(false, 0).Match(lists), 2)
+	s.assert.Len(trades.NewMatchingEngine(false, 1).Match(lists), 1)
+}
+
+func (s *matchingTestSuite) TestRun() {
+	offers := []*trades.TradeOffer{
+		{
+			ID:           "1",
+			OwnerID:      "a",
+			OfferedItems: []*trades.Item{{ID: "sword", Quantity: 1}},
+			WantedItems:  []*trades.Item{{ID: "shield", Quantity: 1}},
+		},
+		{
+			ID:           "2",
+			OwnerID:      "b",
+			OfferedItems: []*trades.Item{{ID: "shield", Quantity: 1}},
+			WantedItems:  []*trades.Item{{ID: "sword", Quantity: 1}},
+		},
+	}
+
+	s.repository.On("GetByStatus").Return(&trades.ResultTradeOffers{Trades: offers, Token: "2"}, nil)
+	s.suggestionsRepository.
+		On("Replace", testifymock.MatchedBy(func(suggestions []*trades.Suggestion) bool {
+			return len(suggestions) == 1 && suggestions[0].Generation != ""
+		})).
+		Return(nil)
+
+	err := s.service.Run(s.ctx)
+
+	s.assert.NoError(err)
+
+	s.repository.AssertNumberOfCalls(s.T(), "GetByStatus", 1)
+	s.suggestionsRepository.AssertNumberOfCalls(s.T(), "Replace", 1)
+}
+
+func (s *matchingTestSuite) TestGet() {
+	suggestion := &trades.Suggestion{
+		ID:      "1",
+		UserIDs: []string{"a", "b"},
+		Participants: []*trades.Participant{
+			{UserID: "a", GivenItems: []*trades.Item{{ID: "sword", Quantity: 1}}, ReceivedItems: []*trades.Item{{ID: "shield", Quantity: 1}}},
+			{UserID: "b", GivenItems: []*trades.Item{{ID: "shield", Quantity: 1}}, ReceivedItems: []*trades.Item{{ID: "sword", Quantity: 1}}},
+		},
+	}
+
+	s.suggestionsRepository.On("Get", "b").Return(&trades.ResultSuggestions{Suggestions: []*trades.Suggestion{suggestion}}, nil)
+
+	res, err := s.service.Get(s.ctx, "b", &trades.GetTradeOffersRequest{})
+
+	s.assert.NoError(err)
+	s.assert.Len(res.Suggestions, 1)
+	s.assert.Equal("b", res.Suggestions[0].Offer.OwnerID)
+	s.assert.Equal("a", res.Suggestions[0].Offer.WantedItemsOwnerID)
+	s.assert.Equal("shield", res.Suggestions[0].Offer.OfferedItems[0].ID)
+}
+
+func BenchmarkMatch(b *testing.B) {
+	const (
+		users        = 20000
+		items        = 50000
+		itemsPerList = 5
+	)
+
+	random := rand.New(rand.NewSource(1))
+	lists := make([]*trades.WantList, users)
+
+	for i := range lists {
+		list := &trades.WantList{UserID: fmt.Sprintf("user-%05d", i)}
+
+		for j := 0; j < itemsPerList; j++ {
+			list.Haves = append(list.Haves, &trades.Item{ID: fmt.Sprintf("item-%d", random.Intn(items)), Quantity: 1})
+			list.Wants = append(list.Wants, &trades.Item{ID: fmt.Sprintf("item-%d", random.Intn(items)), Quantity: 1})
+		}
+
+		lists[i] = list
+	}
+
+	engine := trades.NewMatchingEngine(true, 20)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		engine.Match(lists)
+	}
+}
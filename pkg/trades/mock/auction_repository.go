@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// AuctionRepositoryMock ...
+type AuctionRepositoryMock struct {
+	mock.Mock
+}
+
+// NewAuctionRepository ...
+func NewAuctionRepository() trades.AuctionRepository {
+	return &AuctionRepositoryMock{}
+}
+
+// Insert ...
+func (r *AuctionRepositoryMock) Insert(ctx context.Context, auction *trades.Auction) error {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Update ...
+func (r *AuctionRepositoryMock) Update(ctx context.Context, auction *trades.Auction) error {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Get ...
+func (r *AuctionRepositoryMock) Get(ctx context.Context, req *trades.GetAuctions) (*trades.ResultAuctions, error) {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultAuctions), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetByID ...
+func (r *AuctionRepositoryMock) GetByID(ctx context.Context, id string) (*trades.Auction, error) {
+	args := r.Mock.Called(id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.Auction), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetExpired ...
+func (r *AuctionRepositoryMock) GetExpired(ctx context.Context, now, staleBefore time.Time, limit int64) ([]*trades.Auction, error) {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.([]*trades.Auction), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// AddBid ...
+func (r *AuctionRepositoryMock) AddBid(ctx context.Context, id string, bid *trades.Bid) error {
+	args := r.Mock.Called(id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// UpdateStatus ...
+func (r *AuctionRepositoryMock) UpdateStatus(ctx context.Context, auction *trades.Auction, from trades.AuctionStatus) (bool, error) {
+	args := r.Mock.Called(from)
+
+	arg1 := args.Get(1)
+	if arg1 != nil {
+		return false, arg1.(error)
+	}
+
+	return args.Bool(0), nil
+}
+
+// ClaimStale ...
+func (r *AuctionRepositoryMock) ClaimStale(ctx context.Context, auction *trades.Auction, staleBefore time.Time) (bool, error) {
+	args := r.Mock.Called(auction.ID)
+
+	arg1 := args.Get(1)
+	if arg1 != nil {
+		return false, arg1.(error)
+	}
+
+	return args.Bool(0), nil
+}
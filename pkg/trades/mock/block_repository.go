@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// BlockRepositoryMock ...
+type BlockRepositoryMock struct {
+	mock.Mock
+}
+
+// NewBlockRepository ...
+func NewBlockRepository() trades.BlockRepository {
+	return &BlockRepositoryMock{}
+}
+
+// Insert ...
+func (r *BlockRepositoryMock) Insert(ctx context.Context, block *trades.Block) error {
+	args := r.Mock.Called(block.BlockerID, block.BlockedID)
+
+	return args.Error(0)
+}
+
+// Delete ...
+func (r *BlockRepositoryMock) Delete(ctx context.Context, blockerID, blockedID string) error {
+	args := r.Mock.Called(blockerID, blockedID)
+
+	return args.Error(0)
+}
+
+// Get ...
+func (r *BlockRepositoryMock) Get(ctx context.Context, blockerID string, req *trades.GetTradesOffers) (*trades.ResultBlocks, error) {
+	args := r.Mock.Called(blockerID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultBlocks), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Exists ...
+func (r *BlockRepositoryMock) Exists(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	args := r.Mock.Called(blockerID, blockedID)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// GetBlockedIDs ...
+func (r *BlockRepositoryMock) GetBlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+	args := r.Mock.Called(blockerID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.([]string), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// FeedbackRepositoryMock ...
+type FeedbackRepositoryMock struct {
+	mock.Mock
+}
+
+// NewFeedbackRepository ...
+func NewFeedbackRepository() trades.FeedbackRepository {
+	return &FeedbackRepositoryMock{}
+}
+
+// Insert ...
+func (r *FeedbackRepositoryMock) Insert(ctx context.Context, feedback *trades.Feedback) error {
+	args := r.Mock.Called(feedback.AuthorID)
+
+	return args.Error(0)
+}
+
+// Get ...
+func (r *FeedbackRepositoryMock) Get(ctx context.Context, recipientID string, req *trades.GetTradesOffers) (*trades.ResultFeedback, error) {
+	args := r.Mock.Called(recipientID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultFeedback), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Summary ...
+func (r *FeedbackRepositoryMock) Summary(ctx context.Context, recipientID string) (*trades.FeedbackSummary, error) {
+	args := r.Mock.Called(recipientID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.FeedbackSummary), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
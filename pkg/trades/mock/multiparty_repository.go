@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// MultiPartyRepositoryMock ...
+type MultiPartyRepositoryMock struct {
+	mock.Mock
+}
+
+// NewMultiPartyRepository ...
+func NewMultiPartyRepository() trades.MultiPartyRepository {
+	return &MultiPartyRepositoryMock{}
+}
+
+// Insert ...
+func (r *MultiPartyRepositoryMock) Insert(ctx context.Context, trade *trades.MultiPartyTrade) error {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Update ...
+func (r *MultiPartyRepositoryMock) Update(ctx context.Context, trade *trades.MultiPartyTrade) error {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Get ...
+func (r *MultiPartyRepositoryMock) Get(ctx context.Context, userID string, req *trades.GetTradesOffers) (*trades.ResultMultiPartyTrades, error) {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultMultiPartyTrades), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetByID ...
+func (r *MultiPartyRepositoryMock) GetByID(ctx context.Context, userID, id string) (*trades.MultiPartyTrade, error) {
+	args := r.Mock.Called(id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.MultiPartyTrade), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Accept ...
+func (r *MultiPartyRepositoryMock) Accept(ctx context.Context, userID, id string, acceptedAt time.Time) (*trades.MultiPartyTrade, error) {
+	args := r.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.MultiPartyTrade), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// UpdateStatus ...
+func (r *MultiPartyRepositoryMock) UpdateStatus(ctx context.Context, trade *trades.MultiPartyTrade, from trades.TradeStatus) (bool, error) {
+	args := r.Mock.Called(from)
+
+	arg1 := args.Get(1)
+	if arg1 != nil {
+		return false, arg1.(error)
+	}
+
+	return args.Bool(0), nil
+}
@@ -0,0 +1,24 @@
+package mock
+
+import (
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// ProducerMock ...
+type ProducerMock struct {
+	mock.Mock
+}
+
+// NewProducer ...
+func NewProducer() trades.Producer {
+	return &ProducerMock{}
+}
+
+// Publish ...
+func (p *ProducerMock) Publish(topicID string, event *core.Event) (string, error) {
+	args := p.Mock.Called(topicID, event.Type)
+
+	return args.String(0), args.Error(1)
+}
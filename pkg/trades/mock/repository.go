@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/d-leme/tradew-trades/pkg/trades"
 	"github.com/stretchr/testify/mock"
@@ -43,7 +44,7 @@ func (r *RepositoryMock) Update(ctx context.Context, trade *trades.TradeOffer) e
 
 // Get ...
 func (r *RepositoryMock) Get(ctx context.Context, userID string, req *trades.GetTradesOffers) (*trades.ResultTradeOffers, error) {
-	args := r.Mock.Called()
+	args := r.Mock.Called(userID, req.BlockedOwnerIDs)
 
 	arg0 := args.Get(0)
 	if arg0 != nil {
@@ -68,3 +69,97 @@ func (r *RepositoryMock) GetByID(ctx context.Context, userID string, id string)
 
 	return nil, arg1.(error)
 }
+
+// Count ...
+func (r *RepositoryMock) Count(ctx context.Context, req *trades.CountTradesOffers) (int64, error) {
+	args := r.Mock.Called()
+
+	arg1 := args.Get(1)
+	if arg1 != nil {
+		return 0, arg1.(error)
+	}
+
+	return args.Get(0).(int64), nil
+}
+
+// GetListings ...
+func (r *RepositoryMock) GetListings(ctx context.Context, req *trades.GetTradeListings) (*trades.ResultTradeOffers, error) {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultTradeOffers), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetByStatus ...
+func (r *RepositoryMock) GetByStatus(ctx context.Context, statuses []trades.TradeStatus, req *trades.GetTradesOffers) (*trades.ResultTradeOffers, error) {
+	args := r.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultTradeOffers), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetByItem ...
+func (r *RepositoryMock) GetByItem(ctx context.Context, itemID string, statuses []trades.TradeStatus, req *trades.GetTradesOffers) (*trades.ResultTradeOffers, error) {
+	args := r.Mock.Called(itemID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultTradeOffers), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Claim ...
+func (r *RepositoryMock) Claim(ctx context.Context, userID, id string, claimedAt time.Time) (*trades.TradeOffer, error) {
+	args := r.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.TradeOffer), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Replace ...
+func (r *RepositoryMock) Replace(ctx context.Context, trade *trades.TradeOffer, from trades.TradeStatus, revision int64) (bool, error) {
+	args := r.Mock.Called(from)
+
+	arg1 := args.Get(1)
+	if arg1 != nil {
+		return false, arg1.(error)
+	}
+
+	return args.Bool(0), nil
+}
+
+// Stats ...
+func (r *RepositoryMock) Stats(ctx context.Context, userID string, req *trades.GetTradeStats) (*trades.TradeStats, error) {
+	args := r.Mock.Called(userID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.TradeStats), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// ReputationRepositoryMock ...
+type ReputationRepositoryMock struct {
+	mock.Mock
+}
+
+// NewReputationRepository ...
+func NewReputationRepository() trades.ReputationRepository {
+	return &ReputationRepositoryMock{}
+}
+
+// Increment ...
+func (r *ReputationRepositoryMock) Increment(ctx context.Context, userID string, outcome trades.TradeOutcome, at time.Time) error {
+	args := r.Mock.Called(userID, outcome)
+
+	return args.Error(0)
+}
+
+// GetByID ...
+func (r *ReputationRepositoryMock) GetByID(ctx context.Context, userID string) (*trades.Reputation, error) {
+	args := r.Mock.Called(userID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.Reputation), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
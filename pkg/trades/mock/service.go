@@ -40,3 +40,15 @@ func (r *InventoryServiceMock) TradesItems(ctx context.Context, req *inventory.T
 
 	return nil
 }
+
+// UnlockItems ...
+func (r *InventoryServiceMock) UnlockItems(ctx context.Context, req *inventory.UnlockItemsRequest) error {
+	args := r.Mock.Called(req.LockedBy)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// SuggestionRepositoryMock ...
+type SuggestionRepositoryMock struct {
+	mock.Mock
+}
+
+// NewSuggestionRepository ...
+func NewSuggestionRepository() trades.SuggestionRepository {
+	return &SuggestionRepositoryMock{}
+}
+
+// Replace ...
+func (r *SuggestionRepositoryMock) Replace(ctx context.Context, generation string, suggestions []*trades.Suggestion) error {
+	args := r.Mock.Called(suggestions)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Get ...
+func (r *SuggestionRepositoryMock) Get(ctx context.Context, userID string, req *trades.GetTradesOffers) (*trades.ResultSuggestions, error) {
+	args := r.Mock.Called(userID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.ResultSuggestions), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
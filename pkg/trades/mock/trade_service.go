@@ -0,0 +1,184 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/stretchr/testify/mock"
+)
+
+// TradeServiceMock ...
+type TradeServiceMock struct {
+	mock.Mock
+}
+
+// NewTradeService ...
+func NewTradeService() trades.Service {
+	return &TradeServiceMock{}
+}
+
+// Create ...
+func (s *TradeServiceMock) Create(ctx context.Context, userID, correlationID string, req *trades.CreateTradeOfferRequest) (*trades.CreateTradeOfferResponse, error) {
+	args := s.Mock.Called(userID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.CreateTradeOfferResponse), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Accept ...
+func (s *TradeServiceMock) Accept(ctx context.Context, userID, correlationID, id string, req *trades.AcceptTradeOfferRequest) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Get ...
+func (s *TradeServiceMock) Get(ctx context.Context, userID string, req *trades.GetTradeOffersRequest) (*trades.GetTradeOffersResponse, error) {
+	args := s.Mock.Called(userID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.GetTradeOffersResponse), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetByID ...
+func (s *TradeServiceMock) GetByID(ctx context.Context, userID, id string) (*trades.GetTradeOfferResponse, error) {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.GetTradeOfferResponse), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// GetListings ...
+func (s *TradeServiceMock) GetListings(ctx context.Context, req *trades.GetTradeListingsRequest) (*trades.GetTradeOffersResponse, error) {
+	args := s.Mock.Called()
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.GetTradeOffersResponse), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Claim ...
+func (s *TradeServiceMock) Claim(ctx context.Context, userID, correlationID, id string) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Update ...
+func (s *TradeServiceMock) Update(ctx context.Context, userID, correlationID, id string, req *trades.UpdateTradeOfferRequest) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Submit ...
+func (s *TradeServiceMock) Submit(ctx context.Context, userID, correlationID, id string) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Instantiate ...
+func (s *TradeServiceMock) Instantiate(ctx context.Context, userID, correlationID, id string, req *trades.InstantiateTemplateRequest) (*trades.CreateTradeOfferResponse, error) {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(*trades.CreateTradeOfferResponse), nil
+	}
+
+	arg1 := args.Get(1)
+
+	return nil, arg1.(error)
+}
+
+// Cancel ...
+func (s *TradeServiceMock) Cancel(ctx context.Context, userID, correlationID, id string) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// Decline ...
+func (s *TradeServiceMock) Decline(ctx context.Context, userID, correlationID, id string) error {
+	args := s.Mock.Called(userID, id)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// InvalidateItem ...
+func (s *TradeServiceMock) InvalidateItem(ctx context.Context, correlationID string, change *trades.ItemChange) error {
+	args := s.Mock.Called(change.ItemID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
+
+// TradeLocked ...
+func (s *TradeServiceMock) TradeLocked(ctx context.Context, correlationID string, trade *trades.TradeOffer) error {
+	args := s.Mock.Called(trade.ID)
+
+	arg0 := args.Get(0)
+	if arg0 != nil {
+		return arg0.(error)
+	}
+
+	return nil
+}
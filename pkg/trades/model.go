@@ -1,6 +1,10 @@
 package trades
 
-import "time"
+import (
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
 
 // ItemModel ...
 type ItemModel struct {
@@ -10,12 +14,20 @@ type ItemModel struct {
 
 // TradeOfferModel ...
 type TradeOfferModel struct {
-	ID           string       `json:"id"`
-	Status       string       `json:"status"`
-	OfferedItems []*ItemModel `json:"offered_items"`
-	WantedItems  []*ItemModel `json:"wanted_items"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    *time.Time   `json:"updated_at"`
+	ID                 string       `json:"id"`
+	OwnerID            string       `json:"owner_id"`
+	WantedItemsOwnerID string       `json:"wanted_items_owner_id"`
+	Status             string       `json:"status"`
+	Public             bool         `json:"public"`
+	OfferedItems       []*ItemModel `json:"offered_items"`
+	WantedItems        []*ItemModel `json:"wanted_items"`
+	OfferedCurrency    int64        `json:"offered_currency"`
+	WantedCurrency     int64        `json:"wanted_currency"`
+	Revision           int64        `json:"revision"`
+	ContentHash        string       `json:"content_hash"`
+	Reason             string       `json:"reason,omitempty"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          *time.Time   `json:"updated_at"`
 }
 
 // CreateTradeOfferRequest ...
@@ -23,6 +35,41 @@ type CreateTradeOfferRequest struct {
 	WantedItemsOwnerID string       `json:"wanted_items_owner_id"`
 	OfferedItems       []*ItemModel `json:"offered_items"`
 	WantedItems        []*ItemModel `json:"wanted_items"`
+	OfferedCurrency    int64        `json:"offered_currency"`
+	WantedCurrency     int64        `json:"wanted_currency"`
+	Public             bool         `json:"public"`
+	Draft              bool         `json:"draft"`
+	Template           bool         `json:"template"`
+}
+
+// UpdateTradeOfferRequest fields left empty keep their current value
+type UpdateTradeOfferRequest struct {
+	WantedItemsOwnerID *string      `json:"wanted_items_owner_id"`
+	OfferedItems       []*ItemModel `json:"offered_items"`
+	WantedItems        []*ItemModel `json:"wanted_items"`
+	OfferedCurrency    *int64       `json:"offered_currency"`
+	WantedCurrency     *int64       `json:"wanted_currency"`
+}
+
+// AcceptTradeOfferRequest both fields are optional, when given the offer is
+// only accepted if it still matches them
+type AcceptTradeOfferRequest struct {
+	ExpectedContentHash string `json:"expected_content_hash"`
+	ExpectedRevision    *int64 `json:"expected_revision"`
+}
+
+// InstantiateTemplateRequest ...
+type InstantiateTemplateRequest struct {
+	WantedItemsOwnerID string `json:"wanted_items_owner_id"`
+	Draft              bool   `json:"draft"`
+}
+
+// GetTradeListingsRequest ...
+type GetTradeListingsRequest struct {
+	OfferedItemID string  `form:"offered_item_id"`
+	WantedItemID  string  `form:"wanted_item_id"`
+	Token         *string `form:"token"`
+	PageSize      int64   `form:"page_size"`
 }
 
 // CreateTradeOfferResponse ...
@@ -68,12 +115,20 @@ func ParseItemSlice(s []*Item) []*ItemModel {
 // ParseTradeOffer ...
 func ParseTradeOffer(trade *TradeOffer) *TradeOfferModel {
 	return &TradeOfferModel{
-		ID:           trade.ID,
-		Status:       string(trade.Status),
-		OfferedItems: ParseItemSlice(trade.OfferedItems),
-		WantedItems:  ParseItemSlice(trade.WantedItems),
-		CreatedAt:    trade.CreatedAt,
-		UpdatedAt:    trade.UpdatedAt,
+		ID:                 trade.ID,
+		OwnerID:            trade.OwnerID,
+		WantedItemsOwnerID: trade.WantedItemsOwnerID,
+		Status:             string(trade.Status),
+		Public:             trade.Public,
+		OfferedItems:       ParseItemSlice(trade.OfferedItems),
+		WantedItems:        ParseItemSlice(trade.WantedItems),
+		OfferedCurrency:    trade.OfferedCurrency,
+		WantedCurrency:     trade.WantedCurrency,
+		Revision:           trade.Revision,
+		ContentHash:        trade.ContentHash(),
+		Reason:             trade.Reason,
+		CreatedAt:          trade.CreatedAt,
+		UpdatedAt:          trade.UpdatedAt,
 	}
 }
 
@@ -96,19 +151,31 @@ func ParseGetTradeOffersResponse(res *ResultTradeOffers) *GetTradeOffersResponse
 	}
 }
 
-// ToDomain ...
-func ToDomain(models []*ItemModel) ([]*Item, error) {
+// ToDomain parses the item models, reporting violations under the given path
+func ToDomain(path string, models []*ItemModel) ([]*Item, error) {
 	items := make([]*Item, len(models))
+	validation := core.NewValidation()
 
 	for i, item := range models {
+		itemPath := core.IndexPath(path, i)
+
+		if item == nil {
+			validation.Add(itemPath, core.RuleRequired, "item is required")
+			continue
+		}
 
 		newItem, err := NewItem(item.ID, item.Quantity)
 		if err != nil {
-			return nil, err
+			validation.Merge(itemPath, err)
+			continue
 		}
 
 		items[i] = newItem
 	}
 
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
 	return items, nil
 }
@@ -0,0 +1,213 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auctionRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewAuctionRepository ...
+func NewAuctionRepository(client *mongo.Client, database string) trades.AuctionRepository {
+	repository := &auctionRepositoryMongoDB{client.Database(database).Collection("auctions")}
+	repository.createIndex()
+
+	return repository
+}
+
+// Insert ...
+func (repository *auctionRepositoryMongoDB) Insert(ctx context.Context, auction *trades.Auction) error {
+
+	_, err := repository.collection.InsertOne(ctx, auction)
+
+	return err
+}
+
+// Update ...
+func (repository *auctionRepositoryMongoDB) Update(ctx context.Context, auction *trades.Auction) error {
+
+	filter := bson.M{"_id": auction.ID}
+
+	_, err := repository.collection.UpdateOne(ctx, filter, bson.M{"$set": auction})
+
+	return err
+}
+
+// Get ...
+func (repository *auctionRepositoryMongoDB) Get(ctx context.Context, req *trades.GetAuctions) (*trades.ResultAuctions, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultAuctions)
+	result.Auctions = []*trades.Auction{}
+
+	filter := bson.M{"status": trades.AuctionOpen}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Auctions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Auctions) > 0 {
+		result.Token = result.Auctions[len(result.Auctions)-1].ID
+	}
+
+	return result, nil
+}
+
+// GetByID ...
+func (repository *auctionRepositoryMongoDB) GetByID(ctx context.Context, id string) (*trades.Auction, error) {
+	var result *trades.Auction
+
+	err := repository.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetExpired ...
+func (repository *auctionRepositoryMongoDB) GetExpired(ctx context.Context, now, staleBefore time.Time, limit int64) ([]*trades.Auction, error) {
+	result := []*trades.Auction{}
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": trades.AuctionOpen, "ends_at": bson.M{"$lte": now}},
+			{"status": trades.AuctionClosing, "updated_at": bson.M{"$lte": staleBefore}},
+		},
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"ends_at": 1}).SetLimit(limit),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddBid ...
+func (repository *auctionRepositoryMongoDB) AddBid(ctx context.Context, id string, bid *trades.Bid) error {
+
+	filter := bson.M{
+		"_id":     id,
+		"status":  trades.AuctionOpen,
+		"ends_at": bson.M{"$gt": bid.CreatedAt},
+	}
+
+	update := bson.M{
+		"$push": bson.M{"bids": bid},
+		"$set":  bson.M{"updated_at": bid.CreatedAt},
+	}
+
+	res, err := repository.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateStatus ...
+func (repository *auctionRepositoryMongoDB) UpdateStatus(ctx context.Context, auction *trades.Auction, from trades.AuctionStatus) (bool, error) {
+
+	filter := bson.M{"_id": auction.ID, "status": from}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     auction.Status,
+			"updated_at": auction.UpdatedAt,
+		},
+	}
+
+	res, err := repository.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+// ClaimStale ...
+func (repository *auctionRepositoryMongoDB) ClaimStale(ctx context.Context, auction *trades.Auction, staleBefore time.Time) (bool, error) {
+
+	filter := bson.M{
+		"_id":        auction.ID,
+		"status":     trades.AuctionClosing,
+		"updated_at": bson.M{"$lte": staleBefore},
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": auction.UpdatedAt}}
+
+	res, err := repository.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (repository *auctionRepositoryMongoDB) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	repository.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "ends_at", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "updated_at", Value: 1},
+			},
+		},
+	})
+}
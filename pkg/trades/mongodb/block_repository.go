@@ -0,0 +1,157 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type blockRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewBlockRepository ...
+func NewBlockRepository(client *mongo.Client, database string) trades.BlockRepository {
+	repository := &blockRepositoryMongoDB{client.Database(database).Collection("blocks")}
+	repository.createIndex()
+
+	return repository
+}
+
+// Insert ...
+func (repository *blockRepositoryMongoDB) Insert(ctx context.Context, block *trades.Block) error {
+
+	_, err := repository.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": block.ID},
+		bson.M{"$setOnInsert": block},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// Delete ...
+func (repository *blockRepositoryMongoDB) Delete(ctx context.Context, blockerID, blockedID string) error {
+
+	res, err := repository.collection.DeleteOne(ctx, bson.M{"blocker_id": blockerID, "blocked_id": blockedID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+// Get ...
+func (repository *blockRepositoryMongoDB) Get(ctx context.Context, blockerID string, req *trades.GetTradesOffers) (*trades.ResultBlocks, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultBlocks)
+	result.Blocks = []*trades.Block{}
+
+	filter := bson.M{"blocker_id": blockerID}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Blocks) > 0 {
+		result.Token = result.Blocks[len(result.Blocks)-1].ID
+	}
+
+	return result, nil
+}
+
+// Exists ...
+func (repository *blockRepositoryMongoDB) Exists(ctx context.Context, blockerID, blockedID string) (bool, error) {
+
+	count, err := repository.collection.CountDocuments(
+		ctx,
+		bson.M{"blocker_id": blockerID, "blocked_id": blockedID},
+		options.Count().SetLimit(1),
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetBlockedIDs ...
+func (repository *blockRepositoryMongoDB) GetBlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		bson.M{"blocker_id": blockerID},
+		options.Find().SetProjection(bson.M{"blocked_id": 1}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var blocks []*trades.Block
+
+	if err := cursor.All(ctx, &blocks); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(blocks))
+	for i, block := range blocks {
+		ids[i] = block.BlockedID
+	}
+
+	return ids, nil
+}
+
+func (repository *blockRepositoryMongoDB) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	repository.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "blocker_id", Value: 1},
+				{Key: "blocked_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "blocker_id", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		},
+	})
+}
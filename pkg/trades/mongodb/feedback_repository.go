@@ -0,0 +1,150 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type feedbackRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewFeedbackRepository ...
+func NewFeedbackRepository(client *mongo.Client, database string) trades.FeedbackRepository {
+	repository := &feedbackRepositoryMongoDB{client.Database(database).Collection("feedback")}
+	repository.createIndex()
+
+	return repository
+}
+
+// Insert ...
+func (repository *feedbackRepositoryMongoDB) Insert(ctx context.Context, feedback *trades.Feedback) error {
+
+	_, err := repository.collection.InsertOne(ctx, feedback)
+
+	// the unique trade_id and author_id index rejects a second feedback
+	if mongo.IsDuplicateKeyError(err) {
+		return core.ErrFeedbackAlreadySubmitted
+	}
+
+	return err
+}
+
+// Get ...
+func (repository *feedbackRepositoryMongoDB) Get(ctx context.Context, recipientID string, req *trades.GetTradesOffers) (*trades.ResultFeedback, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultFeedback)
+	result.Feedback = []*trades.Feedback{}
+
+	filter := bson.M{"recipient_id": recipientID}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Feedback)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Feedback) > 0 {
+		result.Token = result.Feedback[len(result.Feedback)-1].ID
+	}
+
+	return result, nil
+}
+
+type feedbackSummaryResult struct {
+	Rating int   `bson:"_id"`
+	Count  int64 `bson:"count"`
+}
+
+// Summary ...
+func (repository *feedbackRepositoryMongoDB) Summary(ctx context.Context, recipientID string) (*trades.FeedbackSummary, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"recipient_id": recipientID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$rating", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := repository.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var results []*feedbackSummaryResult
+
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return toFeedbackSummary(results), nil
+}
+
+func toFeedbackSummary(results []*feedbackSummaryResult) *trades.FeedbackSummary {
+	summary := &trades.FeedbackSummary{Ratings: map[int]int64{}}
+
+	var total int64
+
+	for _, result := range results {
+		summary.Ratings[result.Rating] = result.Count
+		summary.Count += result.Count
+		total += int64(result.Rating) * result.Count
+	}
+
+	if summary.Count > 0 {
+		summary.Average = float64(total) / float64(summary.Count)
+	}
+
+	return summary
+}
+
+func (repository *feedbackRepositoryMongoDB) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	repository.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "trade_id", Value: 1},
+				{Key: "author_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "recipient_id", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "recipient_id", Value: 1},
+				{Key: "rating", Value: 1},
+			},
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type feedbackTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestFeedbackTestSuite(t *testing.T) {
+	suite.Run(t, new(feedbackTestSuite))
+}
+
+func (s *feedbackTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *feedbackTestSuite) TestToFeedbackSummary() {
+	summary := toFeedbackSummary([]*feedbackSummaryResult{
+		{Rating: 5, Count: 3},
+		{Rating: 1, Count: 1},
+	})
+
+	s.assert.Equal(int64(4), summary.Count)
+	s.assert.Equal(float64(4), summary.Average)
+	s.assert.Equal(int64(3), summary.Ratings[5])
+}
+
+func (s *feedbackTestSuite) TestToFeedbackSummaryEmpty() {
+	summary := toFeedbackSummary(nil)
+
+	s.assert.Equal(int64(0), summary.Count)
+	s.assert.Equal(float64(0), summary.Average)
+}
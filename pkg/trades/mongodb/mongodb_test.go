@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testDatabase connects to the server in MONGODB_TEST_URI and returns a
+// database dropped after the test, tests that run queries are skipped when
+// it is not set
+func testDatabase(t *testing.T) (*mongo.Client, string) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	database := "tradew_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	t.Cleanup(func() {
+		client.Database(database).Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+
+	return client, database
+}
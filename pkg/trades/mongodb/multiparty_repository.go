@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type multiPartyRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewMultiPartyRepository ...
+func NewMultiPartyRepository(client *mongo.Client, database string) trades.MultiPartyRepository {
+	repository := &multiPartyRepositoryMongoDB{client.Database(database).Collection("multi_party_trades")}
+	repository.createIndex()
+
+	return repository
+}
+
+// Insert ...
+func (repository *multiPartyRepositoryMongoDB) Insert(ctx context.Context, trade *trades.MultiPartyTrade) error {
+
+	_, err := repository.collection.InsertOne(ctx, trade)
+
+	return err
+}
+
+// Update ...
+func (repository *multiPartyRepositoryMongoDB) Update(ctx context.Context, trade *trades.MultiPartyTrade) error {
+
+	filter := bson.M{"_id": trade.ID}
+
+	_, err := repository.collection.UpdateOne(ctx, filter, bson.M{"$set": trade})
+
+	return err
+}
+
+// Get ...
+func (repository *multiPartyRepositoryMongoDB) Get(ctx context.Context, userID string, req *trades.GetTradesOffers) (*trades.ResultMultiPartyTrades, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultMultiPartyTrades)
+	result.Trades = []*trades.MultiPartyTrade{}
+
+	filter := bson.M{"participants.user_id": userID}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Trades) > 0 {
+		result.Token = result.Trades[len(result.Trades)-1].ID
+	}
+
+	return result, nil
+}
+
+// GetByID ...
+func (repository *multiPartyRepositoryMongoDB) GetByID(ctx context.Context, userID, id string) (*trades.MultiPartyTrade, error) {
+	var result *trades.MultiPartyTrade
+
+	filter := bson.M{"_id": id, "participants.user_id": userID}
+
+	err := repository.collection.FindOne(ctx, filter).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Accept ...
+func (repository *multiPartyRepositoryMongoDB) Accept(ctx context.Context, userID, id string, acceptedAt time.Time) (*trades.MultiPartyTrade, error) {
+	var result *trades.MultiPartyTrade
+
+	filter := bson.M{
+		"_id":    id,
+		"status": trades.TradePending,
+		"participants": bson.M{
+			"$elemMatch": bson.M{"user_id": userID, "accepted_at": nil},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"participants.$.accepted_at": acceptedAt,
+			"updated_at":                 acceptedAt,
+		},
+	}
+
+	err := repository.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateStatus ...
+func (repository *multiPartyRepositoryMongoDB) UpdateStatus(ctx context.Context, trade *trades.MultiPartyTrade, from trades.TradeStatus) (bool, error) {
+
+	filter := bson.M{"_id": trade.ID, "status": from}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     trade.Status,
+			"updated_at": trade.UpdatedAt,
+		},
+	}
+
+	res, err := repository.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (repository *multiPartyRepositoryMongoDB) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	repository.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "participants.user_id", Value: 1}},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/d-leme/tradew-trades/pkg/core"
 	"github.com/d-leme/tradew-trades/pkg/trades"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -50,7 +51,25 @@ func (repository *repositoryMongoDB) Get(ctx context.Context, userID string, req
 	result := new(trades.ResultTradeOffers)
 	result.Trades = []*trades.TradeOffer{}
 
-	filter := bson.M{}
+	// drafts and templates were never sent so they only show up for the owner
+	received := bson.M{
+		"wanted_items_owner_id": userID,
+		"status":                bson.M{"$nin": bson.A{trades.TradeDraft, trades.TradeTemplate}},
+	}
+
+	if len(req.BlockedOwnerIDs) > 0 {
+		received["$nor"] = bson.A{
+			bson.M{"owner_id": bson.M{"$in": req.BlockedOwnerIDs}, "status": trades.TradePending},
+		}
+	}
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"owner_id": userID},
+			received,
+		},
+	}
+
 	if req.Token != nil {
 		filter["_id"] = bson.M{"$gt": req.Token}
 	}
@@ -72,8 +91,8 @@ func (repository *repositoryMongoDB) Get(ctx context.Context, userID string, req
 		return nil, err
 	}
 
-	if lastItem := result.Trades[len(result.Trades)-1]; lastItem != nil {
-		result.Token = lastItem.ID
+	if len(result.Trades) > 0 {
+		result.Token = result.Trades[len(result.Trades)-1].ID
 	}
 
 	return result, nil
@@ -85,15 +104,290 @@ func (repository *repositoryMongoDB) GetByID(ctx context.Context, userID, id str
 
 	err := repository.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
 
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Count ...
+func (repository *repositoryMongoDB) Count(ctx context.Context, req *trades.CountTradesOffers) (int64, error) {
+
+	filter := bson.M{}
+
+	if req.OwnerID != "" {
+		filter["owner_id"] = req.OwnerID
+	}
+
+	if req.WantedItemsOwnerID != "" {
+		filter["wanted_items_owner_id"] = req.WantedItemsOwnerID
+	}
+
+	if len(req.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": req.Statuses}
+	}
+
+	return repository.collection.CountDocuments(ctx, filter)
+}
+
+// GetByStatus ...
+func (repository *repositoryMongoDB) GetByStatus(ctx context.Context, statuses []trades.TradeStatus, req *trades.GetTradesOffers) (*trades.ResultTradeOffers, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultTradeOffers)
+	result.Trades = []*trades.TradeOffer{}
+
+	filter := bson.M{"status": bson.M{"$in": statuses}}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
 	if err != nil {
 		return nil, err
 	}
 
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Trades) > 0 {
+		result.Token = result.Trades[len(result.Trades)-1].ID
+	}
+
 	return result, nil
 }
 
+// GetByItem ...
+func (repository *repositoryMongoDB) GetByItem(ctx context.Context, itemID string, statuses []trades.TradeStatus, req *trades.GetTradesOffers) (*trades.ResultTradeOffers, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultTradeOffers)
+	result.Trades = []*trades.TradeOffer{}
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"offered_items.id": itemID},
+			bson.M{"wanted_items.id": itemID},
+		},
+		"status": bson.M{"$in": statuses},
+	}
+
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Trades) > 0 {
+		result.Token = result.Trades[len(result.Trades)-1].ID
+	}
+
+	return result, nil
+}
+
+// GetListings ...
+func (repository *repositoryMongoDB) GetListings(ctx context.Context, req *trades.GetTradeListings) (*trades.ResultTradeOffers, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultTradeOffers)
+	result.Trades = []*trades.TradeOffer{}
+
+	filter := bson.M{"public": true, "status": trades.TradeListed}
+
+	if req.OfferedItemID != "" {
+		filter["offered_items.id"] = req.OfferedItemID
+	}
+
+	if req.WantedItemID != "" {
+		filter["wanted_items.id"] = req.WantedItemID
+	}
+
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Trades) > 0 {
+		result.Token = result.Trades[len(result.Trades)-1].ID
+	}
+
+	return result, nil
+}
+
+// Claim ...
+func (repository *repositoryMongoDB) Claim(ctx context.Context, userID, id string, claimedAt time.Time) (*trades.TradeOffer, error) {
+	var result *trades.TradeOffer
+
+	filter := bson.M{
+		"_id":                   id,
+		"public":                true,
+		"status":                trades.TradeListed,
+		"wanted_items_owner_id": "",
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"wanted_items_owner_id": userID,
+			"status":                trades.TradeAccepted,
+			"updated_at":            claimedAt,
+		},
+	}
+
+	err := repository.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Stats ...
+func (repository *repositoryMongoDB) Stats(ctx context.Context, userID string, req *trades.GetTradeStats) (*trades.TradeStats, error) {
+
+	cursor, err := repository.collection.Aggregate(ctx, statsPipeline(userID, req))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var results []*statsResult
+
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) < 1 {
+		return (&statsResult{}).toDomain(), nil
+	}
+
+	return results[0].toDomain(), nil
+}
+
+// Replace ...
+func (repository *repositoryMongoDB) Replace(ctx context.Context, trade *trades.TradeOffer, from trades.TradeStatus, revision int64) (bool, error) {
+
+	filter := bson.M{"_id": trade.ID, "status": from, "revision": revision}
+
+	// offers created before revisions existed have none stored
+	if revision == 0 {
+		filter["revision"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	res, err := repository.collection.UpdateOne(ctx, filter, bson.M{"$set": trade})
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount == 1, nil
+}
+
 func (repository *repositoryMongoDB) createIndex() {
-	_, close := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
 	defer close()
 
+	repository.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "owner_id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "owner_id", Value: 1},
+				{Key: "wanted_items_owner_id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "wanted_items_owner_id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "public", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "offered_items.id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "wanted_items.id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+	})
 }
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reputationRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewReputationRepository ...
+func NewReputationRepository(client *mongo.Client, database string) trades.ReputationRepository {
+	return &reputationRepositoryMongoDB{client.Database(database).Collection("reputations")}
+}
+
+// Increment ...
+func (repository *reputationRepositoryMongoDB) Increment(ctx context.Context, userID string, outcome trades.TradeOutcome, at time.Time) error {
+
+	// counters are stored under the outcome name so one upsert covers all of them
+	update := bson.M{
+		"$inc": bson.M{string(outcome): 1},
+		"$max": bson.M{"updated_at": at},
+	}
+
+	_, err := repository.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		update,
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// GetByID ...
+func (repository *reputationRepositoryMongoDB) GetByID(ctx context.Context, userID string) (*trades.Reputation, error) {
+	var result *trades.Reputation
+
+	err := repository.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, core.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
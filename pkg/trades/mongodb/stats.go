@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type statusCount struct {
+	Status trades.TradeStatus `bson:"_id"`
+	Count  int64              `bson:"count"`
+}
+
+// median in milliseconds as returned by $subtract on dates
+type median struct {
+	Value float64 `bson:"value"`
+}
+
+type statsResult struct {
+	Sent     []*statusCount      `bson:"sent"`
+	Received []*statusCount      `bson:"received"`
+	Median   []*median           `bson:"median"`
+	Items    []*trades.ItemStats `bson:"items"`
+}
+
+// statsPipeline aggregates every stat of the user in a single $facet so the
+// offers are only matched once
+func statsPipeline(userID string, req *trades.GetTradeStats) mongo.Pipeline {
+
+	// drafts and templates were never sent, they are left out of every stat
+	match := bson.M{
+		"$or": bson.A{
+			bson.M{"owner_id": userID},
+			bson.M{"wanted_items_owner_id": userID},
+		},
+		"status": bson.M{"$nin": bson.A{trades.TradeDraft, trades.TradeTemplate}},
+	}
+
+	createdAt := bson.M{}
+
+	if req.From != nil {
+		createdAt["$gte"] = *req.From
+	}
+
+	if req.To != nil {
+		createdAt["$lt"] = *req.To
+	}
+
+	if len(createdAt) > 0 {
+		match["created_at"] = createdAt
+	}
+
+	countByStatus := bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}
+	completed := bson.M{"$match": bson.M{"status": trades.TradeCompleted}}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.M{
+			"sent": bson.A{
+				bson.M{"$match": bson.M{"owner_id": userID}},
+				countByStatus,
+			},
+			"received": bson.A{
+				bson.M{"$match": bson.M{"wanted_items_owner_id": userID}},
+				countByStatus,
+			},
+			"median": medianCompletion(completed),
+			"items": bson.A{
+				completed,
+				bson.M{"$project": bson.M{"items": bson.M{"$concatArrays": bson.A{"$offered_items", "$wanted_items"}}}},
+				bson.M{"$unwind": "$items"},
+				bson.M{"$group": bson.M{
+					"_id":      "$items.id",
+					"trades":   bson.M{"$sum": 1},
+					"quantity": bson.M{"$sum": "$items.quantity"},
+				}},
+				bson.M{"$sort": bson.D{{Key: "trades", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": req.TopItems},
+			},
+		}}},
+	}
+}
+
+// medianCompletion numbers the completed offers by duration and averages the
+// middle one, or the two middle ones for an even count, so only those leave
+// the facet
+func medianCompletion(completed bson.M) bson.A {
+	half := bson.M{"$divide": bson.A{"$total", 2}}
+
+	return bson.A{
+		completed,
+		bson.M{"$match": bson.M{"updated_at": bson.M{"$ne": nil}}},
+		bson.M{"$project": bson.M{"duration": bson.M{"$subtract": bson.A{"$updated_at", "$created_at"}}}},
+		bson.M{"$setWindowFields": bson.M{
+			"sortBy": bson.M{"duration": 1},
+			"output": bson.M{
+				"position": bson.M{"$documentNumber": bson.M{}},
+				"total": bson.M{
+					"$count": bson.M{},
+					"window": bson.M{"documents": bson.A{"unbounded", "unbounded"}},
+				},
+			},
+		}},
+		bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{
+			"$position",
+			bson.A{
+				bson.M{"$ceil": half},
+				bson.M{"$add": bson.A{bson.M{"$floor": half}, 1}},
+			},
+		}}}},
+		bson.M{"$group": bson.M{"_id": nil, "value": bson.M{"$avg": "$duration"}}},
+	}
+}
+
+func (result *statsResult) toDomain() *trades.TradeStats {
+	stats := &trades.TradeStats{
+		Sent:     toStatusCounts(result.Sent),
+		Received: toStatusCounts(result.Received),
+		TopItems: result.Items,
+	}
+
+	if stats.TopItems == nil {
+		stats.TopItems = []*trades.ItemStats{}
+	}
+
+	if len(result.Median) > 0 {
+		stats.MedianCompletion = time.Duration(result.Median[0].Value * float64(time.Millisecond))
+	}
+
+	return stats
+}
+
+func toStatusCounts(counts []*statusCount) map[trades.TradeStatus]int64 {
+	result := make(map[trades.TradeStatus]int64, len(counts))
+
+	for _, count := range counts {
+		result[count.Status] = count.Count
+	}
+
+	return result
+}
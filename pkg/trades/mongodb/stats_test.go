@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type statsTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(statsTestSuite))
+}
+
+func (s *statsTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *statsTestSuite) TestStatsPipeline() {
+	userID := uuid.NewString()
+
+	pipeline := statsPipeline(userID, &trades.GetTradeStats{TopItems: 5})
+
+	s.assert.Len(pipeline, 2)
+
+	match := pipeline[0].Map()["$match"].(bson.M)
+	s.assert.NotContains(match, "created_at")
+	s.assert.Len(match["$or"], 2)
+	s.assert.Equal(bson.M{"$nin": bson.A{trades.TradeDraft, trades.TradeTemplate}}, match["status"])
+
+	facet := pipeline[1].Map()["$facet"].(bson.M)
+	s.assert.Contains(facet, "sent")
+	s.assert.Contains(facet, "received")
+	s.assert.Contains(facet, "median")
+
+	items := facet["items"].(bson.A)
+	s.assert.Equal(bson.M{"$limit": int64(5)}, items[len(items)-1])
+}
+
+func (s *statsTestSuite) TestStatsPipelineDateRange() {
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now()
+
+	pipeline := statsPipeline(uuid.NewString(), &trades.GetTradeStats{From: &from, To: &to, TopItems: 5})
+
+	match := pipeline[0].Map()["$match"].(bson.M)
+	s.assert.Equal(bson.M{"$gte": from, "$lt": to}, match["created_at"])
+}
+
+func (s *statsTestSuite) TestStatsPipelineMarshals() {
+	from := time.Now()
+
+	_, err := bson.Marshal(bson.M{"pipeline": statsPipeline(uuid.NewString(), &trades.GetTradeStats{From: &from})})
+
+	s.assert.NoError(err)
+}
+
+func (s *statsTestSuite) TestToDomain() {
+	result := &statsResult{
+		Sent: []*statusCount{
+			{Status: trades.TradeCompleted, Count: 3},
+			{Status: trades.TradeCancelled, Count: 1},
+		},
+		Received: []*statusCount{
+			{Status: trades.TradeDeclined, Count: 2},
+			{Status: trades.TradePending, Count: 4},
+		},
+		Median: []*median{{Value: 2500}},
+	}
+
+	stats := result.toDomain()
+
+	s.assert.Equal(int64(3), stats.Sent[trades.TradeCompleted])
+	s.assert.Equal(int64(4), stats.Received[trades.TradePending])
+	s.assert.Equal(0.5, stats.CompletionRate())
+	s.assert.Equal(2500*time.Millisecond, stats.MedianCompletion)
+	s.assert.NotNil(stats.TopItems)
+}
+
+func (s *statsTestSuite) TestStatsMedianEven() {
+	repository, userID := s.seedStats(4*time.Second, time.Second, 3*time.Second, 2*time.Second)
+
+	stats, err := repository.Stats(context.Background(), userID, &trades.GetTradeStats{TopItems: 5})
+
+	s.assert.NoError(err)
+	s.assert.Equal(2500*time.Millisecond, stats.MedianCompletion)
+	s.assert.Equal(int64(4), stats.Sent[trades.TradeCompleted])
+	s.assert.Equal(int64(1), stats.Sent[trades.TradePending])
+}
+
+func (s *statsTestSuite) TestStatsMedianOdd() {
+	repository, userID := s.seedStats(3*time.Second, time.Second, 2*time.Second)
+
+	stats, err := repository.Stats(context.Background(), userID, &trades.GetTradeStats{TopItems: 5})
+
+	s.assert.NoError(err)
+	s.assert.Equal(2*time.Second, stats.MedianCompletion)
+}
+
+func (s *statsTestSuite) TestStatsWithoutCompletedOffers() {
+	repository, userID := s.seedStats()
+
+	stats, err := repository.Stats(context.Background(), userID, &trades.GetTradeStats{TopItems: 5})
+
+	s.assert.NoError(err)
+	s.assert.Equal(time.Duration(0), stats.MedianCompletion)
+	s.assert.Equal(int64(1), stats.Sent[trades.TradePending])
+	s.assert.NotContains(stats.Sent, trades.TradeDraft)
+	s.assert.NotContains(stats.Sent, trades.TradeTemplate)
+}
+
+// seedStats inserts one completed offer per duration, a pending offer, a draft
+// and a template
+func (s *statsTestSuite) seedStats(durations ...time.Duration) (trades.Repository, string) {
+	client, database := testDatabase(s.T())
+	repository := NewRepository(client, database)
+
+	userID := uuid.NewString()
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+
+	offers := []*trades.TradeOffer{
+		{ID: uuid.NewString(), OwnerID: userID, WantedItemsOwnerID: uuid.NewString(), Status: trades.TradePending, CreatedAt: createdAt},
+		{ID: uuid.NewString(), OwnerID: userID, Status: trades.TradeDraft, CreatedAt: createdAt},
+		{ID: uuid.NewString(), OwnerID: userID, Status: trades.TradeTemplate, CreatedAt: createdAt},
+	}
+
+	for _, duration := range durations {
+		updatedAt := createdAt.Add(duration)
+
+		offers = append(offers, &trades.TradeOffer{
+			ID:                 uuid.NewString(),
+			OwnerID:            userID,
+			WantedItemsOwnerID: uuid.NewString(),
+			Status:             trades.TradeCompleted,
+			OfferedItems:       []*trades.Item{{ID: uuid.NewString(), Quantity: 1}},
+			WantedItems:        []*trades.Item{},
+			CreatedAt:          createdAt,
+			UpdatedAt:          &updatedAt,
+		})
+	}
+
+	for _, offer := range offers {
+		s.Require().NoError(repository.Insert(context.Background(), offer))
+	}
+
+	return repository, userID
+}
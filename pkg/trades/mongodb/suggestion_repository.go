@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const suggestionsInsertBatchSize = 1000
+
+type suggestionRepositoryMongoDB struct {
+	collection *mongo.Collection
+}
+
+// NewSuggestionRepository ...
+func NewSuggestionRepository(client *mongo.Client, database string) trades.SuggestionRepository {
+	repository := &suggestionRepositoryMongoDB{client.Database(database).Collection("suggestions")}
+	repository.createIndex()
+
+	return repository
+}
+
+// Replace ...
+func (repository *suggestionRepositoryMongoDB) Replace(ctx context.Context, generation string, suggestions []*trades.Suggestion) error {
+
+	for start := 0; start < len(suggestions); start += suggestionsInsertBatchSize {
+		end := start + suggestionsInsertBatchSize
+		if end > len(suggestions) {
+			end = len(suggestions)
+		}
+
+		documents := make([]interface{}, 0, end-start)
+		for _, suggestion := range suggestions[start:end] {
+			documents = append(documents, suggestion)
+		}
+
+		if _, err := repository.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	_, err := repository.collection.DeleteMany(ctx, bson.M{"generation": bson.M{"$ne": generation}})
+
+	return err
+}
+
+// Get ...
+func (repository *suggestionRepositoryMongoDB) Get(ctx context.Context, userID string, req *trades.GetTradesOffers) (*trades.ResultSuggestions, error) {
+
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	result := new(trades.ResultSuggestions)
+	result.Suggestions = []*trades.Suggestion{}
+
+	filter := bson.M{"user_ids": userID}
+	if req.Token != nil {
+		filter["_id"] = bson.M{"$gt": req.Token}
+	}
+
+	cursor, err := repository.collection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(req.PageSize),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result.Suggestions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Suggestions) > 0 {
+		result.Token = result.Suggestions[len(result.Suggestions)-1].ID
+	}
+
+	return result, nil
+}
+
+func (repository *suggestionRepositoryMongoDB) createIndex() {
+	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
+	defer close()
+
+	repository.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_ids", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "generation", Value: 1}}},
+	})
+}
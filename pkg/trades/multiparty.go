@@ -0,0 +1,226 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+const (
+	// RuleBalanced violated when items given and received by the participants do not match
+	RuleBalanced = "balanced"
+
+	// RuleOwnerParticipant violated when the owner of a multi-party trade is not participating
+	RuleOwnerParticipant = "owner-participant"
+)
+
+// Participant ...
+type Participant struct {
+	UserID        string     `bson:"user_id"`
+	GivenItems    []*Item    `bson:"given_items"`
+	ReceivedItems []*Item    `bson:"received_items"`
+	AcceptedAt    *time.Time `bson:"accepted_at"`
+}
+
+// MultiPartyTrade trade between N users where each one gives and receives items
+type MultiPartyTrade struct {
+	ID           string         `bson:"_id"`
+	OwnerID      string         `bson:"owner_id"`
+	Status       TradeStatus    `bson:"status"`
+	Participants []*Participant `bson:"participants"`
+	CreatedAt    time.Time      `bson:"created_at"`
+	UpdatedAt    *time.Time     `bson:"updated_at"`
+}
+
+// ResultMultiPartyTrades ...
+type ResultMultiPartyTrades struct {
+	Trades []*MultiPartyTrade
+	Token  string
+}
+
+// MultiPartyRepository ...
+type MultiPartyRepository interface {
+	Insert(ctx context.Context, trade *MultiPartyTrade) error
+	Update(ctx context.Context, trade *MultiPartyTrade) error
+	Get(ctx context.Context, userID string, req *GetTradesOffers) (*ResultMultiPartyTrades, error)
+	GetByID(ctx context.Context, userID, id string) (*MultiPartyTrade, error)
+
+	// Accept atomically marks the participant as accepted while the trade is pending
+	// and returns the updated trade, core.ErrNotFound is returned if nothing matched
+	Accept(ctx context.Context, userID, id string, acceptedAt time.Time) (*MultiPartyTrade, error)
+
+	// UpdateStatus persists the trade status only if the stored one still equals from
+	UpdateStatus(ctx context.Context, trade *MultiPartyTrade, from TradeStatus) (bool, error)
+}
+
+// MultiPartyService ...
+type MultiPartyService interface {
+	Create(ctx context.Context, userID, correlationID string, req *CreateMultiPartyTradeRequest) (*CreateTradeOfferResponse, error)
+	Accept(ctx context.Context, userID, correlationID, id string) error
+	Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetMultiPartyTradesResponse, error)
+	GetByID(ctx context.Context, userID, id string) (*GetMultiPartyTradeResponse, error)
+}
+
+// NewParticipant ...
+func NewParticipant(userID string, givenItems, receivedItems []*Item) (*Participant, error) {
+
+	validation := core.NewValidation()
+
+	if userID == "" {
+		validation.Add("user_id", core.RuleRequired, "user id is required")
+	}
+
+	if len(givenItems) < 1 && len(receivedItems) < 1 {
+		validation.Add("", core.RuleMin, "participant must give or receive at least one item")
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Participant{
+		UserID:        userID,
+		GivenItems:    givenItems,
+		ReceivedItems: receivedItems,
+	}, nil
+}
+
+// NewMultiPartyTrade creates a trade already accepted by its owner, every item
+// given by a participant must be received by another one in the same quantity
+func NewMultiPartyTrade(id, ownerID string, participants []*Participant) (*MultiPartyTrade, error) {
+
+	validation := core.NewValidation()
+
+	if id == "" {
+		validation.Add("id", core.RuleRequired, "id is required")
+	}
+
+	if ownerID == "" {
+		validation.Add("owner_id", core.RuleRequired, "owner id is required")
+	}
+
+	if len(participants) < 2 {
+		validation.Add("participants", core.RuleMin, "at least two participants are required")
+	}
+
+	validateParticipants(validation, ownerID, participants)
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for _, participant := range participants {
+		if participant.UserID == ownerID {
+			participant.AcceptedAt = &now
+		}
+	}
+
+	return &MultiPartyTrade{
+		ID:           id,
+		OwnerID:      ownerID,
+		Status:       TradeCreated,
+		Participants: participants,
+		CreatedAt:    now,
+	}, nil
+}
+
+// Participant returns the participant with the given user id or nil
+func (trade *MultiPartyTrade) Participant(userID string) *Participant {
+	for _, participant := range trade.Participants {
+		if participant.UserID == userID {
+			return participant
+		}
+	}
+
+	return nil
+}
+
+// AllAccepted reports whether every participant has accepted the trade
+func (trade *MultiPartyTrade) AllAccepted() bool {
+	for _, participant := range trade.Participants {
+		if participant.AcceptedAt == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdateStatus ...
+func (trade *MultiPartyTrade) UpdateStatus(status TradeStatus) {
+	trade.Status = status
+
+	now := time.Now()
+	trade.UpdatedAt = &now
+}
+
+type givenItem struct {
+	giverID  string
+	quantity int64
+	received int64
+}
+
+func validateParticipants(validation *core.Validation, ownerID string, participants []*Participant) {
+	users := map[string]bool{}
+	given := map[string]*givenItem{}
+	isOwnerParticipant := false
+
+	for i, participant := range participants {
+		path := core.IndexPath("participants", i)
+
+		if users[participant.UserID] {
+			validation.Add(core.JoinPath(path, "user_id"), core.RuleUnique, "participant is duplicated")
+		}
+
+		users[participant.UserID] = true
+		isOwnerParticipant = isOwnerParticipant || participant.UserID == ownerID
+
+		for j, item := range participant.GivenItems {
+			if _, exists := given[item.ID]; exists {
+				validation.Add(core.JoinPath(path, core.IndexPath("given_items", j)), core.RuleUnique, "item is given more than once")
+				continue
+			}
+
+			given[item.ID] = &givenItem{giverID: participant.UserID, quantity: item.Quantity}
+		}
+	}
+
+	if ownerID != "" && !isOwnerParticipant {
+		validation.Add("participants", RuleOwnerParticipant, "owner must be a participant")
+	}
+
+	for i, participant := range participants {
+		path := core.IndexPath("participants", i)
+
+		for j, item := range participant.ReceivedItems {
+			g, exists := given[item.ID]
+			if !exists || g.giverID == participant.UserID {
+				validation.Add(
+					core.JoinPath(path, core.IndexPath("received_items", j)),
+					RuleBalanced,
+					"item is not given by another participant",
+				)
+				continue
+			}
+
+			g.received += item.Quantity
+		}
+	}
+
+	for i, participant := range participants {
+		path := core.IndexPath("participants", i)
+
+		for j, item := range participant.GivenItems {
+			if g := given[item.ID]; g.giverID == participant.UserID && g.received != g.quantity {
+				validation.Add(
+					core.JoinPath(path, core.IndexPath("given_items", j)),
+					RuleBalanced,
+					"given quantity must match the received quantity",
+				)
+			}
+		}
+	}
+}
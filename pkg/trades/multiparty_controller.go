@@ -0,0 +1,103 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// MultiPartyController ...
+type MultiPartyController struct {
+	authenticate *core.Authenticate
+	service      MultiPartyService
+}
+
+// NewMultiPartyController ...
+func NewMultiPartyController(authenticate *core.Authenticate, service MultiPartyService) MultiPartyController {
+	return MultiPartyController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *MultiPartyController) RegisterRoutes(r *gin.RouterGroup) {
+	trades := r.Group("/trades/multi")
+	{
+		trades.Use(
+			c.authenticate.Middleware(),
+		)
+
+		trades.POST("", c.post)
+		trades.POST("accept/:id", c.accept)
+		trades.GET("", c.get)
+		trades.GET(":id", c.getByID)
+	}
+}
+
+func (c *MultiPartyController) post(ctx *gin.Context) {
+	req := new(CreateMultiPartyTradeRequest)
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Create(ctx, userID, correlationID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *MultiPartyController) accept(ctx *gin.Context) {
+	correlationID := ctx.GetString("X-Correlation-ID")
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.service.Accept(ctx, userID, correlationID, id); err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *MultiPartyController) get(ctx *gin.Context) {
+	req := new(GetTradeOffersRequest)
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, userID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *MultiPartyController) getByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	res, err := c.service.GetByID(ctx, userID, id)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,128 @@
+package trades
+
+import (
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// ParticipantModel ...
+type ParticipantModel struct {
+	UserID        string       `json:"user_id"`
+	GivenItems    []*ItemModel `json:"given_items"`
+	ReceivedItems []*ItemModel `json:"received_items"`
+	AcceptedAt    *time.Time   `json:"accepted_at"`
+}
+
+// MultiPartyTradeModel ...
+type MultiPartyTradeModel struct {
+	ID           string              `json:"id"`
+	OwnerID      string              `json:"owner_id"`
+	Status       string              `json:"status"`
+	Participants []*ParticipantModel `json:"participants"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    *time.Time          `json:"updated_at"`
+}
+
+// CreateParticipantRequest ...
+type CreateParticipantRequest struct {
+	UserID        string       `json:"user_id"`
+	GivenItems    []*ItemModel `json:"given_items"`
+	ReceivedItems []*ItemModel `json:"received_items"`
+}
+
+// CreateMultiPartyTradeRequest ...
+type CreateMultiPartyTradeRequest struct {
+	Participants []*CreateParticipantRequest `json:"participants"`
+}
+
+// GetMultiPartyTradesResponse ...
+type GetMultiPartyTradesResponse struct {
+	Trades []*MultiPartyTradeModel `json:"trades"`
+	Token  string                  `json:"token"`
+}
+
+// GetMultiPartyTradeResponse ...
+type GetMultiPartyTradeResponse struct {
+	Trade *MultiPartyTradeModel `json:"trade"`
+}
+
+// ParseParticipant ...
+func ParseParticipant(participant *Participant) *ParticipantModel {
+	return &ParticipantModel{
+		UserID:        participant.UserID,
+		GivenItems:    ParseItemSlice(participant.GivenItems),
+		ReceivedItems: ParseItemSlice(participant.ReceivedItems),
+		AcceptedAt:    participant.AcceptedAt,
+	}
+}
+
+// ParseMultiPartyTrade ...
+func ParseMultiPartyTrade(trade *MultiPartyTrade) *MultiPartyTradeModel {
+	participants := make([]*ParticipantModel, len(trade.Participants))
+
+	for i, participant := range trade.Participants {
+		participants[i] = ParseParticipant(participant)
+	}
+
+	return &MultiPartyTradeModel{
+		ID:           trade.ID,
+		OwnerID:      trade.OwnerID,
+		Status:       string(trade.Status),
+		Participants: participants,
+		CreatedAt:    trade.CreatedAt,
+		UpdatedAt:    trade.UpdatedAt,
+	}
+}
+
+// ParseGetMultiPartyTradesResponse ...
+func ParseGetMultiPartyTradesResponse(res *ResultMultiPartyTrades) *GetMultiPartyTradesResponse {
+	trades := make([]*MultiPartyTradeModel, len(res.Trades))
+
+	for i, trade := range res.Trades {
+		trades[i] = ParseMultiPartyTrade(trade)
+	}
+
+	return &GetMultiPartyTradesResponse{
+		Token:  res.Token,
+		Trades: trades,
+	}
+}
+
+// ParticipantsToDomain parses the participant requests, reporting violations under the given path
+func ParticipantsToDomain(path string, models []*CreateParticipantRequest) ([]*Participant, error) {
+	participants := make([]*Participant, len(models))
+	validation := core.NewValidation()
+
+	for i, model := range models {
+		participantPath := core.IndexPath(path, i)
+
+		if model == nil {
+			validation.Add(participantPath, core.RuleRequired, "participant is required")
+			continue
+		}
+
+		givenItems, givenErr := ToDomain(core.JoinPath(participantPath, "given_items"), model.GivenItems)
+		receivedItems, receivedErr := ToDomain(core.JoinPath(participantPath, "received_items"), model.ReceivedItems)
+
+		if givenErr != nil || receivedErr != nil {
+			validation.Merge("", givenErr)
+			validation.Merge("", receivedErr)
+			continue
+		}
+
+		participant, err := NewParticipant(model.UserID, givenItems, receivedItems)
+		if err != nil {
+			validation.Merge(participantPath, err)
+			continue
+		}
+
+		participants[i] = participant
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
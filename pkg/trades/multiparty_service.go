@@ -0,0 +1,269 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type multiPartyService struct {
+	repository       MultiPartyRepository
+	inventoryService inventory.Service
+}
+
+// NewMultiPartyService ...
+func NewMultiPartyService(repository MultiPartyRepository, inventoryService inventory.Service) MultiPartyService {
+	return &multiPartyService{
+		repository:       repository,
+		inventoryService: inventoryService,
+	}
+}
+
+func (s *multiPartyService) Create(
+	ctx context.Context,
+	userID, correlationID string,
+	req *CreateMultiPartyTradeRequest,
+) (*CreateTradeOfferResponse, error) {
+
+	fields := logrus.Fields{
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	participants, err := ParticipantsToDomain("participants", req.Participants)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error parsing participants")
+		return nil, err
+	}
+
+	trade, err := NewMultiPartyTrade(uuid.NewString(), userID, participants)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error creating new multi-party trade")
+		return nil, err
+	}
+
+	if err := s.repository.Insert(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error inserting multi-party trade")
+		return nil, err
+	}
+
+	fields["trade_id"] = trade.ID
+	logrus.WithFields(fields).Info("new multi-party trade created")
+
+	lockItemsReq := &inventory.LockItemsRequest{
+		LockedBy:     trade.ID,
+		Participants: make([]*inventory.ParticipantItemsToLock, len(trade.Participants)),
+	}
+
+	for i, participant := range trade.Participants {
+		items := make([]*inventory.ItemToLock, len(participant.GivenItems))
+
+		for j, item := range participant.GivenItems {
+			items[j] = &inventory.ItemToLock{
+				ID:       item.ID,
+				Quantity: item.Quantity,
+			}
+		}
+
+		lockItemsReq.Participants[i] = &inventory.ParticipantItemsToLock{
+			OwnerID: participant.UserID,
+			Items:   items,
+		}
+	}
+
+	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error locking items")
+
+		trade.UpdateStatus(TradeError)
+
+		if err := s.repository.Update(ctx, trade); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating multi-party trade")
+			return nil, err
+		}
+
+		logrus.WithFields(fields).Info("multi-party trade status set to error")
+
+		return nil, core.ErrLockFailed
+	}
+
+	trade.UpdateStatus(TradePending)
+
+	if err := s.repository.Update(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating multi-party trade")
+		return nil, err
+	}
+
+	logrus.WithFields(fields).Info("multi-party trade status set to pending")
+
+	return &CreateTradeOfferResponse{ID: trade.ID}, nil
+}
+
+func (s *multiPartyService) Accept(ctx context.Context, userID, correlationID, id string) error {
+
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting multi-party trade")
+		return err
+	}
+
+	if trade.Status != TradePending {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to accept multi-party trade that was in an invalid state")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	participant := trade.Participant(userID)
+	if participant == nil {
+		logrus.WithError(core.ErrNotFound).WithFields(fields).Error("user is not a participant of the multi-party trade")
+		return core.ErrNotFound
+	}
+
+	if participant.AcceptedAt != nil {
+		logrus.
+			WithError(core.ErrTradeAlreadyAccepted).
+			WithFields(fields).
+			Error("participant already accepted the multi-party trade")
+
+		return core.ErrTradeAlreadyAccepted
+	}
+
+	// the acceptance is recorded atomically so concurrent participants don't overwrite each other
+	trade, err = s.repository.Accept(ctx, userID, id, time.Now())
+	if err == core.ErrNotFound {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("multi-party trade changed while accepting")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error accepting multi-party trade")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("participant accepted multi-party trade")
+
+	if !trade.AllAccepted() {
+		return nil
+	}
+
+	// only the participant that moves the trade out of pending executes it
+	trade.UpdateStatus(TradeAccepted)
+
+	updated, err := s.repository.UpdateStatus(ctx, trade, TradePending)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating multi-party trade")
+		return err
+	}
+
+	if !updated {
+		return nil
+	}
+
+	logrus.WithFields(fields).Info("multi-party trade status set to accepted")
+
+	tradeItemsReq := &inventory.TradeItemsRequest{
+		TradeID:      trade.ID,
+		Participants: make([]*inventory.ParticipantItemsToTrade, len(trade.Participants)),
+	}
+
+	for i, participant := range trade.Participants {
+		tradeItemsReq.Participants[i] = &inventory.ParticipantItemsToTrade{
+			OwnerID:       participant.UserID,
+			GivenItems:    toItemsToTrade(participant.GivenItems),
+			ReceivedItems: toItemsToTrade(participant.ReceivedItems),
+		}
+	}
+
+	if err := s.inventoryService.TradesItems(ctx, tradeItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error trading items")
+
+		trade.UpdateStatus(TradeError)
+
+		if _, err := s.repository.UpdateStatus(ctx, trade, TradeAccepted); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating multi-party trade")
+			return err
+		}
+
+		logrus.WithFields(fields).Info("multi-party trade status set to error")
+
+		return core.ErrItemsTradeFailed
+	}
+
+	trade.UpdateStatus(TradeCompleted)
+
+	if _, err := s.repository.UpdateStatus(ctx, trade, TradeAccepted); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating multi-party trade")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("multi-party trade status set to completed")
+
+	return nil
+}
+
+func (s *multiPartyService) Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetMultiPartyTradesResponse, error) {
+
+	res, err := s.repository.Get(ctx, userID, &GetTradesOffers{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	})
+
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"user_id": userID,
+				"token":   req.Token,
+			}).
+			Error("error getting multi-party trades")
+		return nil, err
+	}
+
+	return ParseGetMultiPartyTradesResponse(res), nil
+}
+
+func (s *multiPartyService) GetByID(ctx context.Context, userID, id string) (*GetMultiPartyTradeResponse, error) {
+
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"trade_id": id,
+				"user_id":  userID,
+			}).
+			Error("error getting multi-party trade")
+		return nil, err
+	}
+
+	return &GetMultiPartyTradeResponse{Trade: ParseMultiPartyTrade(trade)}, nil
+}
+
+func toItemsToTrade(s []*Item) []*inventory.ItemToTrade {
+	items := make([]*inventory.ItemToTrade, len(s))
+
+	for i, item := range s {
+		items[i] = &inventory.ItemToTrade{
+			ID:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,188 @@
+package trades_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type multiPartyServiceTestSuite struct {
+	suite.Suite
+	assert           *assert.Assertions
+	ctx              context.Context
+	repository       *mock.MultiPartyRepositoryMock
+	service          trades.MultiPartyService
+	inventoryService *mock.InventoryServiceMock
+}
+
+func TestMultiPartyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(multiPartyServiceTestSuite))
+}
+
+func (s *multiPartyServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *multiPartyServiceTestSuite) SetupTest() {
+	s.repository = mock.NewMultiPartyRepository().(*mock.MultiPartyRepositoryMock)
+	s.inventoryService = mock.NewInventoryService().(*mock.InventoryServiceMock)
+	s.service = trades.NewMultiPartyService(s.repository, s.inventoryService)
+}
+
+func (s *multiPartyServiceTestSuite) TestCreate() {
+	a, b, c := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	itemA, itemB, itemC := uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	req := &trades.CreateMultiPartyTradeRequest{
+		Participants: []*trades.CreateParticipantRequest{
+			{
+				UserID:        a,
+				GivenItems:    []*trades.ItemModel{{ID: itemA, Quantity: 1}},
+				ReceivedItems: []*trades.ItemModel{{ID: itemC, Quantity: 2}},
+			},
+			{
+				UserID:        b,
+				GivenItems:    []*trades.ItemModel{{ID: itemB, Quantity: 3}},
+				ReceivedItems: []*trades.ItemModel{{ID: itemA, Quantity: 1}},
+			},
+			{
+				UserID:        c,
+				GivenItems:    []*trades.ItemModel{{ID: itemC, Quantity: 2}},
+				ReceivedItems: []*trades.ItemModel{{ID: itemB, Quantity: 3}},
+			},
+		},
+	}
+
+	s.repository.On("Insert").Return(nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Create(s.ctx, a, uuid.NewString(), req)
+
+	s.assert.NoError(err)
+	s.assert.NotNil(res)
+	s.assert.NotEmpty(res.ID)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Update", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+}
+
+func (s *multiPartyServiceTestSuite) TestCreateUnbalanced() {
+	a, b := uuid.NewString(), uuid.NewString()
+	itemA, itemB := uuid.NewString(), uuid.NewString()
+
+	req := &trades.CreateMultiPartyTradeRequest{
+		Participants: []*trades.CreateParticipantRequest{
+			{
+				UserID:        a,
+				GivenItems:    []*trades.ItemModel{{ID: itemA, Quantity: 2}},
+				ReceivedItems: []*trades.ItemModel{{ID: itemB, Quantity: 1}},
+			},
+			{
+				UserID:        b,
+				GivenItems:    []*trades.ItemModel{{ID: itemB, Quantity: 1}},
+				ReceivedItems: []*trades.ItemModel{{ID: itemA, Quantity: 1}},
+			},
+		},
+	}
+
+	res, err := s.service.Create(s.ctx, a, uuid.NewString(), req)
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+	s.assert.Nil(res)
+
+	verr := err.(*core.Error)
+	s.assert.Len(verr.Violations, 1)
+	s.assert.Equal("participants[0].given_items[0]", verr.Violations[0].Path)
+	s.assert.Equal(trades.RuleBalanced, verr.Violations[0].Rule)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *multiPartyServiceTestSuite) TestAcceptWaitingOtherParticipants() {
+	trade := newPendingMultiPartyTrade()
+	participant := trade.Participants[1]
+
+	accepted := *trade
+	now := time.Now()
+	accepted.Participants = []*trades.Participant{
+		trade.Participants[0],
+		{UserID: participant.UserID, AcceptedAt: &now},
+		trade.Participants[2],
+	}
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Accept", participant.UserID, trade.ID).Return(&accepted)
+
+	err := s.service.Accept(s.ctx, participant.UserID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Accept", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "UpdateStatus", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func (s *multiPartyServiceTestSuite) TestAcceptLastParticipant() {
+	trade := newPendingMultiPartyTrade()
+	participant := trade.Participants[2]
+
+	accepted := *trade
+	now := time.Now()
+	accepted.Participants = []*trades.Participant{
+		{UserID: trade.Participants[0].UserID, AcceptedAt: &now},
+		{UserID: trade.Participants[1].UserID, AcceptedAt: &now},
+		{UserID: participant.UserID, AcceptedAt: &now},
+	}
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Accept", participant.UserID, trade.ID).Return(&accepted)
+	s.repository.On("UpdateStatus", trades.TradePending).Return(true, nil)
+	s.repository.On("UpdateStatus", trades.TradeAccepted).Return(true, nil)
+	s.inventoryService.On("TradesItems").Return(nil)
+
+	err := s.service.Accept(s.ctx, participant.UserID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCompleted, accepted.Status)
+
+	s.repository.AssertNumberOfCalls(s.T(), "UpdateStatus", 2)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 1)
+}
+
+func (s *multiPartyServiceTestSuite) TestAcceptTwice() {
+	trade := newPendingMultiPartyTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Accept(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrTradeAlreadyAccepted)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Accept", 0)
+}
+
+func newPendingMultiPartyTrade() *trades.MultiPartyTrade {
+	now := time.Now()
+	ownerID := uuid.NewString()
+
+	return &trades.MultiPartyTrade{
+		ID:      uuid.NewString(),
+		OwnerID: ownerID,
+		Status:  trades.TradePending,
+		Participants: []*trades.Participant{
+			{UserID: ownerID, AcceptedAt: &now},
+			{UserID: uuid.NewString()},
+			{UserID: uuid.NewString()},
+		},
+	}
+}
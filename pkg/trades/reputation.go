@@ -0,0 +1,94 @@
+package trades
+
+import (
+	"context"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// Reputation counters of how the trades a user took part in finished,
+// Errored is no longer incremented and only holds past counts
+type Reputation struct {
+	UserID    string    `bson:"_id"`
+	Completed int64     `bson:"completed"`
+	Declined  int64     `bson:"declined"`
+	Cancelled int64     `bson:"cancelled"`
+	Expired   int64     `bson:"expired"`
+	Errored   int64     `bson:"errored"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// ReputationRepository ...
+type ReputationRepository interface {
+	// Increment adds one outcome to the user counters creating the reputation when missing
+	Increment(ctx context.Context, userID string, outcome TradeOutcome, at time.Time) error
+	GetByID(ctx context.Context, userID string) (*Reputation, error)
+}
+
+// ReputationService ...
+type ReputationService interface {
+	TransitionHandler
+	Get(ctx context.Context, userID string) (*GetReputationResponse, error)
+}
+
+// NewReputation creates the reputation of a user without finished trades
+func NewReputation(userID string) *Reputation {
+	return &Reputation{UserID: userID}
+}
+
+// Score between 0 and 1 of how often the user trades go through. Declines
+// are the recipient's choice so they do not lower it, expired auctions and
+// errors are not counted and users without trades start at 0.5
+func (r *Reputation) Score() float64 {
+	return float64(r.Completed+1) / float64(r.Completed+r.Cancelled+2)
+}
+
+// Finished number of trades the user took part in that a participant ended
+func (r *Reputation) Finished() int64 {
+	return r.Completed + r.Declined + r.Cancelled
+}
+
+// outcomeParticipants returns the users whose reputation an outcome is
+// recorded on, declines are recorded on the owner whose offer was refused. Errors
+// count against nobody since a failed lock or trade can't tell which side of
+// the offer caused it
+func outcomeParticipants(transition *Transition) []string {
+	switch transition.Outcome {
+	case OutcomeErrored:
+		return nil
+	case OutcomeCompleted:
+		if transition.CounterpartyID == "" {
+			return []string{transition.OwnerID}
+		}
+
+		return []string{transition.OwnerID, transition.CounterpartyID}
+	default:
+		return []string{transition.OwnerID}
+	}
+}
+
+// MinReputationRule rejects offers from owners whose score is below minScore
+// once they finished at least minTrades trades, so a few early cancels do not
+// lock new users out
+func MinReputationRule(repository ReputationRepository, minScore float64, minTrades int64) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		// edited offers were already allowed when created
+		if trade.Status == TradePending {
+			return nil
+		}
+
+		reputation, err := repository.GetByID(ctx, trade.OwnerID)
+		if err == core.ErrNotFound {
+			reputation = NewReputation(trade.OwnerID)
+		} else if err != nil {
+			return err
+		}
+
+		if reputation.Finished() >= minTrades && reputation.Score() < minScore {
+			return core.ErrReputationTooLow
+		}
+
+		return nil
+	})
+}
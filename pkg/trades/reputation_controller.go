@@ -0,0 +1,45 @@
+package trades
+
+import (
+	"net/http"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationController ...
+type ReputationController struct {
+	authenticate *core.Authenticate
+	service      ReputationService
+}
+
+// NewReputationController ...
+func NewReputationController(authenticate *core.Authenticate, service ReputationService) ReputationController {
+	return ReputationController{
+		authenticate: authenticate,
+		service:      service,
+	}
+}
+
+// RegisterRoutes ...
+func (c *ReputationController) RegisterRoutes(r *gin.RouterGroup) {
+	users := r.Group("/users")
+	{
+		users.Use(
+			c.authenticate.Middleware(),
+		)
+
+		users.GET(":id/reputation", c.get)
+	}
+}
+
+func (c *ReputationController) get(ctx *gin.Context) {
+	res, err := c.service.Get(ctx, ctx.Param("id"))
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
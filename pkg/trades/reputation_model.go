@@ -0,0 +1,34 @@
+package trades
+
+import "time"
+
+// ReputationModel ...
+type ReputationModel struct {
+	UserID    string    `json:"user_id"`
+	Score     float64   `json:"score"`
+	Completed int64     `json:"completed"`
+	Declined  int64     `json:"declined"`
+	Cancelled int64     `json:"cancelled"`
+	Expired   int64     `json:"expired"`
+	Errored   int64     `json:"errored"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetReputationResponse ...
+type GetReputationResponse struct {
+	Reputation *ReputationModel `json:"reputation"`
+}
+
+// ParseReputation ...
+func ParseReputation(reputation *Reputation) *ReputationModel {
+	return &ReputationModel{
+		UserID:    reputation.UserID,
+		Score:     reputation.Score(),
+		Completed: reputation.Completed,
+		Declined:  reputation.Declined,
+		Cancelled: reputation.Cancelled,
+		Expired:   reputation.Expired,
+		Errored:   reputation.Errored,
+		UpdatedAt: reputation.UpdatedAt,
+	}
+}
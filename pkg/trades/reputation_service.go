@@ -0,0 +1,50 @@
+package trades
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+)
+
+type reputationService struct {
+	repository ReputationRepository
+}
+
+// NewReputationService ...
+func NewReputationService(repository ReputationRepository) ReputationService {
+	return &reputationService{repository: repository}
+}
+
+func (s *reputationService) HandleTransition(ctx context.Context, transition *Transition) error {
+
+	fields := logrus.Fields{
+		"trade_id": transition.TradeID,
+		"outcome":  transition.Outcome,
+	}
+
+	for _, userID := range outcomeParticipants(transition) {
+		if err := s.repository.Increment(ctx, userID, transition.Outcome, transition.OccurredAt); err != nil {
+			logrus.WithError(err).WithFields(fields).WithField("user_id", userID).Error("error updating reputation")
+			return err
+		}
+	}
+
+	logrus.WithFields(fields).Info("reputation updated")
+
+	return nil
+}
+
+func (s *reputationService) Get(ctx context.Context, userID string) (*GetReputationResponse, error) {
+
+	reputation, err := s.repository.GetByID(ctx, userID)
+
+	if err == core.ErrNotFound {
+		reputation = NewReputation(userID)
+	} else if err != nil {
+		logrus.WithError(err).WithField("user_id", userID).Error("error getting reputation")
+		return nil, err
+	}
+
+	return &GetReputationResponse{Reputation: ParseReputation(reputation)}, nil
+}
@@ -0,0 +1,216 @@
+package trades_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type reputationServiceTestSuite struct {
+	suite.Suite
+	assert           *assert.Assertions
+	ctx              context.Context
+	repository       *mock.ReputationRepositoryMock
+	tradeRepository  *mock.RepositoryMock
+	inventoryService *mock.InventoryServiceMock
+	service          trades.ReputationService
+}
+
+func TestReputationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(reputationServiceTestSuite))
+}
+
+func (s *reputationServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *reputationServiceTestSuite) SetupTest() {
+	s.repository = mock.NewReputationRepository().(*mock.ReputationRepositoryMock)
+	s.tradeRepository = mock.NewRepository().(*mock.RepositoryMock)
+	s.inventoryService = mock.NewInventoryService().(*mock.InventoryServiceMock)
+	s.service = trades.NewReputationService(s.repository)
+}
+
+func (s *reputationServiceTestSuite) TestHandleCompleted() {
+	transition := &trades.Transition{
+		TradeID:        uuid.NewString(),
+		OwnerID:        uuid.NewString(),
+		CounterpartyID: uuid.NewString(),
+		Outcome:        trades.OutcomeCompleted,
+		OccurredAt:     time.Now(),
+	}
+
+	s.repository.On("Increment", transition.OwnerID, trades.OutcomeCompleted).Return(nil)
+	s.repository.On("Increment", transition.CounterpartyID, trades.OutcomeCompleted).Return(nil)
+
+	err := s.service.HandleTransition(s.ctx, transition)
+
+	s.assert.NoError(err)
+	s.repository.AssertNumberOfCalls(s.T(), "Increment", 2)
+}
+
+func (s *reputationServiceTestSuite) TestHandleDeclinedCountsAgainstOwner() {
+	transition := &trades.Transition{
+		TradeID:        uuid.NewString(),
+		OwnerID:        uuid.NewString(),
+		CounterpartyID: uuid.NewString(),
+		Outcome:        trades.OutcomeDeclined,
+		OccurredAt:     time.Now(),
+	}
+
+	s.repository.On("Increment", transition.OwnerID, trades.OutcomeDeclined).Return(nil)
+
+	err := s.service.HandleTransition(s.ctx, transition)
+
+	s.assert.NoError(err)
+	s.repository.AssertNumberOfCalls(s.T(), "Increment", 1)
+}
+
+func (s *reputationServiceTestSuite) TestGetWithoutTrades() {
+	userID := uuid.NewString()
+
+	s.repository.On("GetByID", userID).Return(nil, core.ErrNotFound)
+
+	res, err := s.service.Get(s.ctx, userID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(userID, res.Reputation.UserID)
+	s.assert.Equal(0.5, res.Reputation.Score)
+}
+
+func (s *reputationServiceTestSuite) TestScore() {
+	reputation := &trades.Reputation{Completed: 7, Cancelled: 1, Expired: 4}
+
+	s.assert.Equal(0.8, reputation.Score())
+}
+
+func (s *reputationServiceTestSuite) TestCreateBelowMinScore() {
+	userID := uuid.NewString()
+
+	s.repository.On("GetByID", userID).Return(&trades.Reputation{UserID: userID, Cancelled: 5}, nil)
+
+	service := trades.NewService(
+		s.tradeRepository,
+		s.inventoryService,
+		trades.WithRuleEngine(trades.NewDefaultRuleEngine(s.tradeRepository, nil).With(
+			trades.MinReputationRule(s.repository, 0.2, 5),
+		)),
+	)
+
+	res, err := service.Create(s.ctx, userID, uuid.NewString(), &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedItems:       []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		WantedItems:        []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+	})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrReputationTooLow)
+
+	s.tradeRepository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *reputationServiceTestSuite) TestScoreIgnoresDeclines() {
+	reputation := &trades.Reputation{Declined: 4}
+
+	s.assert.Equal(0.5, reputation.Score())
+}
+
+func (s *reputationServiceTestSuite) TestCreateBelowMinScoreWithFewTrades() {
+	userID := uuid.NewString()
+
+	s.repository.On("GetByID", userID).Return(&trades.Reputation{UserID: userID, Cancelled: 4}, nil)
+	s.tradeRepository.On("Insert").Return(nil)
+	s.tradeRepository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	service := trades.NewService(
+		s.tradeRepository,
+		s.inventoryService,
+		trades.WithRuleEngine(trades.NewDefaultRuleEngine(s.tradeRepository, nil).With(
+			trades.MinReputationRule(s.repository, 0.2, 5),
+		)),
+	)
+
+	res, err := service.Create(s.ctx, userID, uuid.NewString(), &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedItems:       []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		WantedItems:        []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+	})
+
+	s.assert.NoError(err)
+	s.assert.NotNil(res)
+
+	s.tradeRepository.AssertNumberOfCalls(s.T(), "Insert", 1)
+}
+
+func (s *reputationServiceTestSuite) TestTradeServiceReportsDecline() {
+	trade := newPendingTrade()
+
+	s.tradeRepository.On("GetByID", trade.ID).Return(trade)
+	s.tradeRepository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+	s.repository.On("Increment", trade.OwnerID, trades.OutcomeDeclined).Return(nil)
+
+	service := trades.NewService(s.tradeRepository, s.inventoryService, trades.WithTransitionHandler(s.service))
+
+	err := service.Decline(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+	s.repository.AssertNumberOfCalls(s.T(), "Increment", 1)
+}
+
+func (s *reputationServiceTestSuite) TestHandleErroredCountsAgainstNobody() {
+	transition := &trades.Transition{
+		TradeID:        uuid.NewString(),
+		OwnerID:        uuid.NewString(),
+		CounterpartyID: uuid.NewString(),
+		Outcome:        trades.OutcomeErrored,
+		OccurredAt:     time.Now(),
+	}
+
+	err := s.service.HandleTransition(s.ctx, transition)
+
+	s.assert.NoError(err)
+	s.repository.AssertNumberOfCalls(s.T(), "Increment", 0)
+}
+
+func (s *reputationServiceTestSuite) TestLockFailedKeepsRecipientScore() {
+	recipientID := uuid.NewString()
+	reputation := &trades.Reputation{UserID: recipientID, Completed: 3, Declined: 1}
+	score := reputation.Score()
+
+	s.tradeRepository.On("Insert").Return(nil)
+	s.tradeRepository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(core.ErrLockFailed)
+	s.repository.On("GetByID", recipientID).Return(reputation, nil)
+
+	service := trades.NewService(s.tradeRepository, s.inventoryService, trades.WithTransitionHandler(s.service))
+
+	_, err := service.Create(s.ctx, uuid.NewString(), uuid.NewString(), &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: recipientID,
+		OfferedItems:       []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+		WantedItems:        []*trades.ItemModel{{ID: uuid.NewString(), Quantity: 1}},
+	})
+
+	s.assert.ErrorIs(err, core.ErrLockFailed)
+	s.repository.AssertNumberOfCalls(s.T(), "Increment", 0)
+
+	res, err := s.service.Get(s.ctx, recipientID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(score, res.Reputation.Score)
+}
+
+func (s *reputationServiceTestSuite) TestScoreIgnoresErrors() {
+	reputation := &trades.Reputation{Completed: 7, Cancelled: 1, Errored: 3}
+
+	s.assert.Equal(0.8, reputation.Score())
+}
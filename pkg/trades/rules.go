@@ -0,0 +1,240 @@
+package trades
+
+import (
+	"context"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+)
+
+// Rule validates a trade offer before it is created
+type Rule interface {
+	Validate(ctx context.Context, trade *TradeOffer) error
+}
+
+// RuleFunc adapts a function to a Rule
+type RuleFunc func(ctx context.Context, trade *TradeOffer) error
+
+// Validate ...
+func (f RuleFunc) Validate(ctx context.Context, trade *TradeOffer) error {
+	return f(ctx, trade)
+}
+
+// RuleEngine runs a set of rules in order and stops on the first broken one
+type RuleEngine struct {
+	rules []Rule
+}
+
+// NewRuleEngine ...
+func NewRuleEngine(rules ...Rule) *RuleEngine {
+	return &RuleEngine{rules: rules}
+}
+
+// NewDefaultRuleEngine creates a RuleEngine with the trade invariants and
+// every limit set in settings, limits equal to zero are not enforced
+func NewDefaultRuleEngine(repository Repository, settings *core.TradeRules) *RuleEngine {
+	rules := []Rule{
+		NoSelfOfferRule(),
+		UniqueItemsRule(),
+		DisjointItemsRule(),
+	}
+
+	if settings == nil {
+		return NewRuleEngine(rules...)
+	}
+
+	if settings.MaxItemsPerSide > 0 {
+		rules = append(rules, MaxItemsPerSideRule(settings.MaxItemsPerSide))
+	}
+
+	if settings.MaxItemQuantity > 0 {
+		rules = append(rules, MaxItemQuantityRule(settings.MaxItemQuantity))
+	}
+
+	if settings.MaxCurrency > 0 {
+		rules = append(rules, MaxCurrencyRule(settings.MaxCurrency))
+	}
+
+	if settings.MaxOpenOffers > 0 {
+		rules = append(rules, MaxOpenOffersRule(repository, settings.MaxOpenOffers))
+	}
+
+	if settings.MaxOffersBetweenUsers > 0 {
+		rules = append(rules, MaxOffersBetweenUsersRule(repository, settings.MaxOffersBetweenUsers))
+	}
+
+	return NewRuleEngine(rules...)
+}
+
+// With returns a new engine running the given rules after the current ones
+func (e *RuleEngine) With(rules ...Rule) *RuleEngine {
+	combined := make([]Rule, 0, len(e.rules)+len(rules))
+	combined = append(combined, e.rules...)
+
+	return NewRuleEngine(append(combined, rules...)...)
+}
+
+// Validate ...
+func (e *RuleEngine) Validate(ctx context.Context, trade *TradeOffer) error {
+	for _, rule := range e.rules {
+		if err := rule.Validate(ctx, trade); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NoSelfOfferRule rejects offers where both sides belong to the same user
+func NoSelfOfferRule() Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if trade.OwnerID == trade.WantedItemsOwnerID {
+			return core.ErrTradeSelfOffer
+		}
+
+		return nil
+	})
+}
+
+// UniqueItemsRule rejects offers with the same item twice in one side
+func UniqueItemsRule() Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if hasDuplicatedItems(trade.OfferedItems) || hasDuplicatedItems(trade.WantedItems) {
+			return core.ErrTradeDuplicatedItems
+		}
+
+		return nil
+	})
+}
+
+// DisjointItemsRule rejects offers with an item both offered and wanted
+func DisjointItemsRule() Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		offered := make(map[string]bool, len(trade.OfferedItems))
+		for _, item := range trade.OfferedItems {
+			offered[item.ID] = true
+		}
+
+		for _, item := range trade.WantedItems {
+			if offered[item.ID] {
+				return core.ErrTradeItemOfferedAndWanted
+			}
+		}
+
+		return nil
+	})
+}
+
+// MaxItemsPerSideRule limits how many items each side of an offer can have
+func MaxItemsPerSideRule(max int) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if len(trade.OfferedItems) > max || len(trade.WantedItems) > max {
+			return core.ErrTradeTooManyItems
+		}
+
+		return nil
+	})
+}
+
+// MaxItemQuantityRule limits the quantity of every item of an offer
+func MaxItemQuantityRule(max int64) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		for _, items := range [][]*Item{trade.OfferedItems, trade.WantedItems} {
+			for _, item := range items {
+				if item.Quantity > max {
+					return core.ErrTradeQuantityLimitExceeded
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// MaxCurrencyRule limits the currency added to any side of an offer
+func MaxCurrencyRule(max int64) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if trade.OfferedCurrency > max || trade.WantedCurrency > max {
+			return core.ErrTradeCurrencyLimitExceeded
+		}
+
+		return nil
+	})
+}
+
+// MaxOpenOffersRule limits how many open offers a user can own, pending
+// offers being edited are already counted so they are not checked again. It
+// is a soft limit, the count and the insert are not atomic so offers created
+// at the same time can go past it by a few
+func MaxOpenOffersRule(repository Repository, max int64) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if trade.Status == TradePending {
+			return nil
+		}
+
+		count, err := repository.Count(ctx, &CountTradesOffers{
+			OwnerID:  trade.OwnerID,
+			Statuses: OpenTradeStatuses,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if count >= max {
+			return core.ErrTradeOpenOffersLimitExceeded
+		}
+
+		return nil
+	})
+}
+
+// MaxOffersBetweenUsersRule limits how many open offers two users can have
+// between them, in any direction. Like MaxOpenOffersRule it is a soft limit
+// that concurrent offers can go past
+func MaxOffersBetweenUsersRule(repository Repository, max int64) Rule {
+	return RuleFunc(func(ctx context.Context, trade *TradeOffer) error {
+		if trade.WantedItemsOwnerID == "" || trade.Status == TradePending {
+			return nil
+		}
+
+		sent, err := repository.Count(ctx, &CountTradesOffers{
+			OwnerID:            trade.OwnerID,
+			WantedItemsOwnerID: trade.WantedItemsOwnerID,
+			Statuses:           OpenTradeStatuses,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		received, err := repository.Count(ctx, &CountTradesOffers{
+			OwnerID:            trade.WantedItemsOwnerID,
+			WantedItemsOwnerID: trade.OwnerID,
+			Statuses:           OpenTradeStatuses,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if sent+received >= max {
+			return core.ErrTradePairOffersLimitExceeded
+		}
+
+		return nil
+	})
+}
+
+func hasDuplicatedItems(items []*Item) bool {
+	seen := make(map[string]bool, len(items))
+
+	for _, item := range items {
+		if seen[item.ID] {
+			return true
+		}
+
+		seen[item.ID] = true
+	}
+
+	return false
+}
@@ -0,0 +1,16 @@
+package trades
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed schemas
+var schemas embed.FS
+
+// EventSchemas JSON Schemas of every version of the published and consumed
+// events, stored as <type>/v<version>.json
+func EventSchemas() fs.FS {
+	sub, _ := fs.Sub(schemas, "schemas")
+	return sub
+}
@@ -2,6 +2,8 @@ package trades
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/d-leme/tradew-trades/pkg/core"
 	"github.com/d-leme/tradew-trades/pkg/trades/external/inventory"
@@ -9,16 +11,67 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const invalidateBatchSize = 100
+
 type service struct {
 	repository       Repository
 	inventoryService inventory.Service
+	rules            *RuleEngine
+	producer         Producer
+	topics           *core.Topics
+	transitions      TransitionHandler
+	blocks           BlockRepository
 }
 
+// ServiceOption ...
+type ServiceOption func(*service)
+
 // NewService ...
-func NewService(repository Repository, inventoryService inventory.Service) Service {
-	return &service{
+func NewService(repository Repository, inventoryService inventory.Service, opts ...ServiceOption) Service {
+	s := &service{
 		repository:       repository,
 		inventoryService: inventoryService,
+		rules:            NewDefaultRuleEngine(repository, nil),
+		topics:           &core.Topics{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithRuleEngine - default enforces only the trade invariants
+func WithRuleEngine(rules *RuleEngine) ServiceOption {
+	return func(s *service) {
+		s.rules = rules
+	}
+}
+
+// WithProducer - default publishes no events
+func WithProducer(producer Producer, topics *core.Topics) ServiceOption {
+	return func(s *service) {
+		s.producer = producer
+
+		if topics != nil {
+			s.topics = topics
+		}
+	}
+}
+
+// WithTransitionHandler - default nobody is told when trades finish
+func WithTransitionHandler(handler TransitionHandler) ServiceOption {
+	return func(s *service) {
+		s.transitions = handler
+	}
+}
+
+// WithBlockRepository - default shows offers from every sender and lets anyone
+// claim a listing
+func WithBlockRepository(blocks BlockRepository) ServiceOption {
+	return func(s *service) {
+		s.blocks = blocks
 	}
 }
 
@@ -34,25 +87,107 @@ func (s *service) Create(
 		"correlation_id":        correlationID,
 	}
 
-	offeredItems, err := ToDomain(req.OfferedItems)
-	if err != nil {
-		logrus.WithError(err).WithFields(fields).Error("error parsing offered items")
+	validation := core.NewValidation()
+
+	offeredItems, err := ToDomain("offered_items", req.OfferedItems)
+	validation.Merge("", err)
+
+	wantedItems, err := ToDomain("wanted_items", req.WantedItems)
+	validation.Merge("", err)
+
+	if err := validation.Err(); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error parsing items")
 		return nil, err
 	}
 
-	wantedItems, err := ToDomain(req.WantedItems)
-	if err != nil {
-		logrus.WithError(err).WithFields(fields).Error("error parsing wanted items")
+	if req.Public && (req.Draft || req.Template) {
+		validation.Add("public", core.RuleForbidden, "public listings can't be saved as drafts or templates")
+	}
+
+	if req.Draft && req.Template {
+		validation.Add("template", core.RuleForbidden, "an offer can't be both a draft and a template")
+	}
+
+	if req.Template && req.WantedItemsOwnerID != "" {
+		validation.Add("wanted_items_owner_id", core.RuleForbidden, "templates can't have a wanted items owner")
+	}
+
+	if req.Public && req.WantedItemsOwnerID != "" {
+		validation.Add("wanted_items_owner_id", core.RuleForbidden, "public listings can't have a wanted items owner")
+	}
+
+	if err := validation.Err(); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error creating new offer")
 		return nil, err
 	}
 
-	trade, err := NewTradeOffer(uuid.NewString(), userID, req.WantedItemsOwnerID, offeredItems, wantedItems)
+	var trade *TradeOffer
+
+	switch {
+	case req.Draft:
+		trade, err = NewTradeDraft(
+			uuid.NewString(),
+			userID,
+			req.WantedItemsOwnerID,
+			offeredItems,
+			wantedItems,
+			req.OfferedCurrency,
+			req.WantedCurrency,
+		)
+	case req.Template:
+		trade, err = NewTradeTemplate(
+			uuid.NewString(),
+			userID,
+			offeredItems,
+			wantedItems,
+			req.OfferedCurrency,
+			req.WantedCurrency,
+		)
+	case req.Public:
+		trade, err = NewTradeListing(
+			uuid.NewString(),
+			userID,
+			offeredItems,
+			wantedItems,
+			req.OfferedCurrency,
+			req.WantedCurrency,
+		)
+	default:
+		trade, err = NewTradeOffer(
+			uuid.NewString(),
+			userID,
+			req.WantedItemsOwnerID,
+			offeredItems,
+			wantedItems,
+			req.OfferedCurrency,
+			req.WantedCurrency,
+		)
+	}
+
 	if err != nil {
 		logrus.WithError(err).WithFields(fields).Error("error creating new offer")
 
 		return nil, err
 	}
 
+	// drafts and templates are only saved, nothing is locked until submitted
+	if trade.Editable() {
+		if err := s.repository.Insert(ctx, trade); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error inserting offer")
+			return nil, err
+		}
+
+		fields["trade_id"] = trade.ID
+		logrus.WithFields(fields).Infof("new trade %s saved", strings.ToLower(string(trade.Status)))
+
+		return &CreateTradeOfferResponse{ID: trade.ID}, nil
+	}
+
+	if err := s.rules.Validate(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("trade offer broke a business rule")
+		return nil, err
+	}
+
 	if err := s.repository.Insert(ctx, trade); err != nil {
 		logrus.WithError(err).WithFields(fields).Error("error inserting offer")
 		return nil, err
@@ -61,57 +196,127 @@ func (s *service) Create(
 	fields["trade_id"] = trade.ID
 	logrus.WithFields(fields).Info("new trade created")
 
-	lockItemsReq := &inventory.LockItemsRequest{
-		LockedBy:           trade.ID,
-		OwnerID:            trade.OwnerID,
-		WantedItemsOwnerID: trade.WantedItemsOwnerID,
-		OfferedItems:       make([]*inventory.ItemToLock, len(trade.OfferedItems)),
-		WantedItems:        make([]*inventory.ItemToLock, len(trade.WantedItems)),
+	if err := s.lockItems(ctx, trade, fields); err != nil {
+		return nil, err
 	}
 
-	for i, item := range trade.OfferedItems {
-		lockItemsReq.OfferedItems[i] = &inventory.ItemToLock{
-			ID:       item.ID,
-			Quantity: item.Quantity,
-		}
+	return &CreateTradeOfferResponse{ID: trade.ID}, nil
+}
+
+func (s *service) Update(
+	ctx context.Context,
+	userID, correlationID, id string,
+	req *UpdateTradeOfferRequest,
+) error {
+
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
 	}
 
-	for i, item := range trade.WantedItems {
-		lockItemsReq.WantedItems[i] = &inventory.ItemToLock{
-			ID:       item.ID,
-			Quantity: item.Quantity,
-		}
+	trade, err := s.getOwned(ctx, userID, id, fields)
+	if err != nil {
+		return err
 	}
 
-	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
-		logrus.WithError(err).WithFields(fields).Error("error locking items")
+	if !trade.Editable() && trade.Status != TradePending {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to update trade that was in an invalid state")
 
-		trade.UpdateStatus(TradeError)
+		return core.ErrTradeInvalidStatus
+	}
 
-		if err := s.repository.Update(ctx, trade); err != nil {
-			logrus.WithError(err).WithFields(fields).Error("error updating trade")
+	validation := core.NewValidation()
 
-			return nil, err
-		}
+	offeredItems, wantedItems := trade.OfferedItems, trade.WantedItems
 
-		logrus.WithFields(fields).Info("trade status set to error")
+	if req.OfferedItems != nil {
+		offeredItems, err = ToDomain("offered_items", req.OfferedItems)
+		validation.Merge("", err)
+	}
+
+	if req.WantedItems != nil {
+		wantedItems, err = ToDomain("wanted_items", req.WantedItems)
+		validation.Merge("", err)
+	}
 
-		return nil, core.ErrLockFailed
+	if err := validation.Err(); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error parsing items")
+		return err
 	}
 
-	trade.UpdateStatus(TradePending)
+	wantedItemsOwnerID := trade.WantedItemsOwnerID
+	if req.WantedItemsOwnerID != nil {
+		wantedItemsOwnerID = *req.WantedItemsOwnerID
+	}
 
-	if err := s.repository.Update(ctx, trade); err != nil {
+	offeredCurrency := trade.OfferedCurrency
+	if req.OfferedCurrency != nil {
+		offeredCurrency = *req.OfferedCurrency
+	}
+
+	wantedCurrency := trade.WantedCurrency
+	if req.WantedCurrency != nil {
+		wantedCurrency = *req.WantedCurrency
+	}
+
+	before := *trade
+
+	if err := trade.Edit(wantedItemsOwnerID, offeredItems, wantedItems, offeredCurrency, wantedCurrency); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error editing trade")
+		return err
+	}
+
+	if trade.Status == TradePending {
+		if err := s.rules.Validate(ctx, trade); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("trade offer broke a business rule")
+			return err
+		}
+
+		if err := s.relockItems(ctx, &before, trade, fields); err != nil {
+			return err
+		}
+
+		s.publish(s.topics.TradeUpdated, &core.Event{
+			Type:          TradeOfferUpdatedEventType,
+			CorrelationID: correlationID,
+			GroupID:       trade.ID,
+			Payload: &TradeOfferUpdatedEvent{
+				TradeID:            trade.ID,
+				OwnerID:            trade.OwnerID,
+				WantedItemsOwnerID: trade.WantedItemsOwnerID,
+				Revision:           trade.Revision,
+				UpdatedAt:          *trade.UpdatedAt,
+			},
+		}, fields)
+
+		return nil
+	}
+
+	updated, err := s.repository.Replace(ctx, trade, before.Status, before.Revision)
+	if err != nil {
 		logrus.WithError(err).WithFields(fields).Error("error updating trade")
-		return nil, err
+		return err
 	}
 
-	logrus.WithFields(fields).Info("trade status set to pending")
+	if !updated {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("trade was submitted while being updated")
 
-	return &CreateTradeOfferResponse{ID: trade.ID}, nil
+		return core.ErrTradeInvalidStatus
+	}
+
+	logrus.WithFields(fields).Info("trade updated")
+
+	return nil
 }
 
-func (s *service) Accept(ctx context.Context, userID, correlationID, id string) error {
+func (s *service) Submit(ctx context.Context, userID, correlationID, id string) error {
 
 	fields := logrus.Fields{
 		"trade_id":       id,
@@ -119,112 +324,765 @@ func (s *service) Accept(ctx context.Context, userID, correlationID, id string)
 		"correlation_id": correlationID,
 	}
 
-	trade, err := s.repository.GetByID(ctx, userID, id)
+	trade, err := s.getOwned(ctx, userID, id, fields)
 	if err != nil {
-		logrus.WithError(err).WithFields(fields).Error("error getting trade")
 		return err
 	}
 
-	if trade.Status != TradePending {
+	if trade.Status != TradeDraft {
 		logrus.
 			WithError(core.ErrTradeInvalidStatus).
 			WithFields(fields).
-			Error("tried to accept trade that was in an invalid state")
+			Error("tried to submit trade that is not a draft")
 
 		return core.ErrTradeInvalidStatus
 	}
 
-	trade.UpdateStatus(TradeAccepted)
+	// drafts skip validation when saved so they are fully validated now
+	_, err = NewTradeOffer(
+		trade.ID,
+		trade.OwnerID,
+		trade.WantedItemsOwnerID,
+		trade.OfferedItems,
+		trade.WantedItems,
+		trade.OfferedCurrency,
+		trade.WantedCurrency,
+	)
 
-	if err := s.repository.Update(ctx, trade); err != nil {
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("draft is not a valid offer")
+		return err
+	}
+
+	if err := s.rules.Validate(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("trade offer broke a business rule")
+		return err
+	}
+
+	trade.UpdateStatus(TradeCreated)
+
+	updated, err := s.repository.Replace(ctx, trade, TradeDraft, trade.Revision)
+	if err != nil {
 		logrus.WithError(err).WithFields(fields).Error("error updating trade")
 		return err
 	}
 
-	logrus.WithFields(fields).Info("trade status set to accepted")
+	if !updated {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("draft was already submitted")
 
-	tradeItemsReq := &inventory.TradeItemsRequest{
-		TradeID:            trade.ID,
-		OwnerID:            trade.OwnerID,
-		WantedItemsOwnerID: trade.WantedItemsOwnerID,
-		OfferedItems:       make([]*inventory.ItemToTrade, len(trade.OfferedItems)),
-		WantedItems:        make([]*inventory.ItemToTrade, len(trade.WantedItems)),
+		return core.ErrTradeInvalidStatus
 	}
 
-	for i, item := range trade.OfferedItems {
-		tradeItemsReq.OfferedItems[i] = &inventory.ItemToTrade{
-			ID:       item.ID,
-			Quantity: item.Quantity,
-		}
+	logrus.WithFields(fields).Info("draft submitted")
+
+	return s.lockItems(ctx, trade, fields)
+}
+
+func (s *service) Instantiate(
+	ctx context.Context,
+	userID, correlationID, id string,
+	req *InstantiateTemplateRequest,
+) (*CreateTradeOfferResponse, error) {
+
+	fields := logrus.Fields{
+		"template_id":    id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
 	}
 
-	for i, item := range trade.WantedItems {
-		tradeItemsReq.WantedItems[i] = &inventory.ItemToTrade{
-			ID:       item.ID,
-			Quantity: item.Quantity,
-		}
+	template, err := s.getOwned(ctx, userID, id, fields)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.inventoryService.TradesItems(ctx, tradeItemsReq); err != nil {
+	if template.Status != TradeTemplate {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to instantiate trade that is not a template")
 
-		trade.UpdateStatus(TradeError)
+		return nil, core.ErrTradeInvalidStatus
+	}
 
-		if err := s.repository.Update(ctx, trade); err != nil {
-			logrus.WithError(err).WithFields(fields).Error("error updating trade")
-			return err
-		}
+	return s.Create(ctx, userID, correlationID, &CreateTradeOfferRequest{
+		WantedItemsOwnerID: req.WantedItemsOwnerID,
+		OfferedItems:       ParseItemSlice(template.OfferedItems),
+		WantedItems:        ParseItemSlice(template.WantedItems),
+		OfferedCurrency:    template.OfferedCurrency,
+		WantedCurrency:     template.WantedCurrency,
+		Draft:              req.Draft,
+	})
+}
 
-		logrus.WithFields(fields).Info("trade status set to error")
+func (s *service) Accept(ctx context.Context, userID, correlationID, id string, req *AcceptTradeOfferRequest) error {
 
-		return core.ErrItemsTradeFailed
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
 	}
 
-	trade.UpdateStatus(TradeCompleted)
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trade")
+		return err
+	}
 
-	if err := s.repository.Update(ctx, trade); err != nil {
+	if trade.WantedItemsOwnerID != userID {
+		logrus.WithError(core.ErrForbidden).WithFields(fields).Error("only the counterparty can accept the trade")
+		return core.ErrForbidden
+	}
+
+	if trade.Status != TradePending {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to accept trade that was in an invalid state")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	if req != nil && !req.matches(trade) {
+		logrus.
+			WithError(core.ErrTradeChanged).
+			WithFields(fields).
+			WithField("revision", trade.Revision).
+			Error("tried to accept a different revision of the trade")
+
+		return core.ErrTradeChanged
+	}
+
+	trade.UpdateStatus(TradeAccepted)
+
+	// fails if the owner edited the offer after it was read
+	updated, err := s.repository.Replace(ctx, trade, TradePending, trade.Revision)
+	if err != nil {
 		logrus.WithError(err).WithFields(fields).Error("error updating trade")
 		return err
 	}
 
-	logrus.WithFields(fields).Info("trade status set to completed")
+	if !updated {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("trade changed while being accepted")
 
-	return nil
+		return core.ErrTradeInvalidStatus
+	}
+
+	logrus.WithFields(fields).Info("trade status set to accepted")
+
+	return s.tradeItems(ctx, trade, fields)
 }
 
-func (s *service) Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetTradeOffersResponse, error) {
+func (s *service) TradeLocked(ctx context.Context, correlationID string, trade *TradeOffer) error {
 
-	res, err := s.repository.Get(ctx, userID, &GetTradesOffers{
-		Token:    req.Token,
-		PageSize: req.PageSize,
+	fields := logrus.Fields{
+		"trade_id":              trade.ID,
+		"user_id":               trade.OwnerID,
+		"wanted_items_owner_id": trade.WantedItemsOwnerID,
+		"correlation_id":        correlationID,
+	}
+
+	trade.UpdateStatus(TradeAccepted)
+
+	if err := s.repository.Insert(ctx, trade); err != nil {
+		// a previous attempt may have inserted it before failing
+		existing, getErr := s.repository.GetByID(ctx, trade.OwnerID, trade.ID)
+		if getErr != nil {
+			logrus.WithError(err).WithFields(fields).Error("error inserting offer")
+			return err
+		}
+
+		switch existing.Status {
+		case TradeCompleted:
+			return nil
+		case TradeError:
+			return core.ErrItemsTradeFailed
+		case TradeAccepted:
+			trade = existing
+		default:
+			logrus.WithError(core.ErrTradeInvalidStatus).WithFields(fields).Error("locked offer exists in an invalid state")
+			return core.ErrTradeInvalidStatus
+		}
+	} else {
+		logrus.WithFields(fields).Info("new trade created from locked items")
+	}
+
+	return s.tradeItems(ctx, trade, fields)
+}
+
+func (s *service) GetListings(ctx context.Context, req *GetTradeListingsRequest) (*GetTradeOffersResponse, error) {
+
+	res, err := s.repository.GetListings(ctx, &GetTradeListings{
+		OfferedItemID: req.OfferedItemID,
+		WantedItemID:  req.WantedItemID,
+		Token:         req.Token,
+		PageSize:      req.PageSize,
 	})
 
 	if err != nil {
 		logrus.
 			WithError(err).
 			WithFields(logrus.Fields{
-				"user_id": userID,
-				"token":   req.Token,
+				"offered_item_id": req.OfferedItemID,
+				"wanted_item_id":  req.WantedItemID,
+				"token":           req.Token,
 			}).
-			Error("error getting trades")
+			Error("error getting listings")
 		return nil, err
 	}
 
 	return ParseGetTradeOffersResponse(res), nil
 }
 
-func (s *service) GetByID(ctx context.Context, userID, id string) (*GetTradeOfferResponse, error) {
+func (s *service) Claim(ctx context.Context, userID, correlationID, id string) error {
+
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
 
 	trade, err := s.repository.GetByID(ctx, userID, id)
 	if err != nil {
-		logrus.
-			WithError(err).
-			WithFields(logrus.Fields{
-				"trade_id": id,
-				"user_id":  userID,
-			}).
-			Error("error getting trades")
-		return nil, err
+		logrus.WithError(err).WithFields(fields).Error("error getting trade")
+		return err
 	}
 
-	return &GetTradeOfferResponse{Trade: ParseTradeOffer(trade)}, nil
+	if !trade.Public || trade.Status != TradeListed {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to claim trade that is not listed")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	if trade.OwnerID == userID {
+		logrus.WithError(core.ErrTradeSelfOffer).WithFields(fields).Error("tried to claim own listing")
+		return core.ErrTradeSelfOffer
+	}
+
+	// claims skip the rule engine, the owner is the one who may have blocked
+	if s.blocks != nil {
+		blocked, err := s.blocks.Exists(ctx, trade.OwnerID, userID)
+		if err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error checking block")
+			return err
+		}
+
+		if blocked {
+			logrus.WithError(core.ErrTradeBlocked).WithFields(fields).Error("listing owner blocked the claimer")
+			return core.ErrTradeBlocked
+		}
+	}
+
+	// binding the counterparty is atomic so only the first claim wins
+	trade, err = s.repository.Claim(ctx, userID, id, time.Now())
+	if err == core.ErrNotFound {
+		logrus.WithError(core.ErrTradeAlreadyClaimed).WithFields(fields).Error("listing was already claimed")
+		return core.ErrTradeAlreadyClaimed
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error claiming trade")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("trade claimed")
+
+	lockItemsReq := newLockItemsRequest(trade)
+	lockItemsReq.OfferedItems = []*inventory.ItemToLock{}
+	lockItemsReq.OfferedCurrency = 0
+
+	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error locking claimer items")
+
+		// releases the listing so other users can still claim it, unless it
+		// was changed since this claim
+		trade.WantedItemsOwnerID = ""
+		trade.UpdateStatus(TradeListed)
+
+		updated, err := s.repository.Replace(ctx, trade, TradeAccepted, trade.Revision)
+		if err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating trade")
+			return err
+		}
+
+		if !updated {
+			logrus.WithFields(fields).Info("trade changed after the claim, keeping it")
+			return core.ErrLockFailed
+		}
+
+		logrus.WithFields(fields).Info("trade status set back to listed")
+
+		return core.ErrLockFailed
+	}
+
+	return s.tradeItems(ctx, trade, fields)
+}
+
+func (s *service) Cancel(ctx context.Context, userID, correlationID, id string) error {
+
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	trade, err := s.getOwned(ctx, userID, id, fields)
+	if err != nil {
+		return err
+	}
+
+	if trade.Status != TradePending && trade.Status != TradeListed && !trade.Editable() {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to cancel trade that was in an invalid state")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	return s.close(ctx, trade, TradeCancelled, fields)
+}
+
+func (s *service) Decline(ctx context.Context, userID, correlationID, id string) error {
+
+	fields := logrus.Fields{
+		"trade_id":       id,
+		"user_id":        userID,
+		"correlation_id": correlationID,
+	}
+
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trade")
+		return err
+	}
+
+	if trade.WantedItemsOwnerID != userID {
+		logrus.WithError(core.ErrForbidden).WithFields(fields).Error("only the counterparty can decline the trade")
+		return core.ErrForbidden
+	}
+
+	if trade.Status != TradePending {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("tried to decline trade that was in an invalid state")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	return s.close(ctx, trade, TradeDeclined, fields)
+}
+
+func (s *service) InvalidateItem(ctx context.Context, correlationID string, change *ItemChange) error {
+
+	fields := logrus.Fields{
+		"item_id":        change.ItemID,
+		"owner_id":       change.OwnerID,
+		"correlation_id": correlationID,
+	}
+
+	// offers failing to close are retried when the event is delivered again,
+	// the ones already invalidated are no longer returned
+	var failed error
+
+	req := &GetTradesOffers{PageSize: invalidateBatchSize}
+
+	for {
+		res, err := s.repository.GetByItem(ctx, change.ItemID, InvalidatableTradeStatuses, req)
+		if err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error getting trades by item")
+			return err
+		}
+
+		for _, trade := range res.Trades {
+			reason := trade.InvalidatedBy(change)
+			if reason == "" {
+				continue
+			}
+
+			trade.Reason = reason
+
+			tradeFields := logrus.Fields{"trade_id": trade.ID, "reason": reason}
+			for key, value := range fields {
+				tradeFields[key] = value
+			}
+
+			if err := s.close(ctx, trade, TradeInvalidated, tradeFields); err != nil && failed == nil {
+				failed = err
+			}
+		}
+
+		if int64(len(res.Trades)) < req.PageSize {
+			return failed
+		}
+
+		token := res.Token
+		req.Token = &token
+	}
+}
+
+func (s *service) Get(ctx context.Context, userID string, req *GetTradeOffersRequest) (*GetTradeOffersResponse, error) {
+
+	fields := logrus.Fields{
+		"user_id": userID,
+		"token":   req.Token,
+	}
+
+	getReq := &GetTradesOffers{
+		Token:    req.Token,
+		PageSize: req.PageSize,
+	}
+
+	if s.blocks != nil {
+		blockedIDs, err := s.blocks.GetBlockedIDs(ctx, userID)
+		if err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error getting blocked users")
+			return nil, err
+		}
+
+		getReq.BlockedOwnerIDs = blockedIDs
+	}
+
+	res, err := s.repository.Get(ctx, userID, getReq)
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trades")
+		return nil, err
+	}
+
+	return ParseGetTradeOffersResponse(res), nil
+}
+
+func (s *service) GetByID(ctx context.Context, userID, id string) (*GetTradeOfferResponse, error) {
+
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"trade_id": id,
+				"user_id":  userID,
+			}).
+			Error("error getting trades")
+		return nil, err
+	}
+
+	// drafts and templates were not sent, only the owner can see them
+	if trade.Editable() && trade.OwnerID != userID {
+		return nil, core.ErrNotFound
+	}
+
+	return &GetTradeOfferResponse{Trade: ParseTradeOffer(trade)}, nil
+}
+
+func (s *service) tradeItems(ctx context.Context, trade *TradeOffer, fields logrus.Fields) error {
+
+	if err := s.inventoryService.TradesItems(ctx, newTradeItemsRequest(trade)); err != nil {
+
+		trade.UpdateStatus(TradeError)
+
+		if err := s.repository.Update(ctx, trade); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating trade")
+			return err
+		}
+
+		logrus.WithFields(fields).Info("trade status set to error")
+
+		s.notify(ctx, trade, OutcomeErrored, fields)
+
+		return core.ErrItemsTradeFailed
+	}
+
+	trade.UpdateStatus(TradeCompleted)
+
+	if err := s.repository.Update(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating trade")
+		return err
+	}
+
+	logrus.WithFields(fields).Info("trade status set to completed")
+
+	s.notify(ctx, trade, OutcomeCompleted, fields)
+
+	return nil
+}
+
+// lockItems locks the items of a created offer and moves it to pending or listed
+func (s *service) lockItems(ctx context.Context, trade *TradeOffer, fields logrus.Fields) error {
+
+	lockItemsReq := newLockItemsRequest(trade)
+
+	// listings have no counterparty yet, only the owner side is locked
+	if trade.Public {
+		lockItemsReq.WantedItems = []*inventory.ItemToLock{}
+		lockItemsReq.WantedCurrency = 0
+	}
+
+	if err := s.inventoryService.LockItems(ctx, lockItemsReq); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error locking items")
+
+		trade.UpdateStatus(TradeError)
+
+		if err := s.repository.Update(ctx, trade); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error updating trade")
+
+			return err
+		}
+
+		logrus.WithFields(fields).Info("trade status set to error")
+
+		s.notify(ctx, trade, OutcomeErrored, fields)
+
+		return core.ErrLockFailed
+	}
+
+	status := TradePending
+	if trade.Public {
+		status = TradeListed
+	}
+
+	trade.UpdateStatus(status)
+
+	if err := s.repository.Update(ctx, trade); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating trade")
+		return err
+	}
+
+	logrus.WithFields(fields).Infof("trade status set to %s", strings.ToLower(string(status)))
+
+	return nil
+}
+
+// relockItems locks what was added to a pending offer before persisting the
+// new revision and only then releases what was removed, so a failed edit
+// leaves the previous revision untouched
+func (s *service) relockItems(ctx context.Context, before, after *TradeOffer, fields logrus.Fields) error {
+
+	added, removed := diffTradeOffers(before, after)
+
+	if !added.empty() {
+		if err := s.inventoryService.LockItems(ctx, added.lockItemsRequest(after)); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("error locking added items")
+			return core.ErrLockFailed
+		}
+	}
+
+	updated, err := s.repository.Replace(ctx, after, TradePending, before.Revision)
+	if err == nil && !updated {
+		err = core.ErrTradeInvalidStatus
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating trade")
+
+		s.unlockItems(ctx, after, added, fields)
+
+		return err
+	}
+
+	fields["revision"] = after.Revision
+	logrus.WithFields(fields).Info("pending trade updated")
+
+	s.unlockItems(ctx, after, removed, fields)
+
+	return nil
+}
+
+// unlockItems releases part of the offer locks, failures are only logged
+// since the offer itself is already consistent
+func (s *service) unlockItems(ctx context.Context, trade *TradeOffer, diff *tradeDiff, fields logrus.Fields) {
+
+	sides := []*inventory.UnlockItemsRequest{
+		{
+			LockedBy: trade.ID,
+			OwnerID:  trade.OwnerID,
+			Items:    toItemsToLock(diff.offeredItems),
+			Currency: diff.offeredCurrency,
+		},
+		{
+			LockedBy: trade.ID,
+			OwnerID:  trade.WantedItemsOwnerID,
+			Items:    toItemsToLock(diff.wantedItems),
+			Currency: diff.wantedCurrency,
+		},
+	}
+
+	for _, req := range sides {
+		if len(req.Items) < 1 && req.Currency < 1 {
+			continue
+		}
+
+		if err := s.inventoryService.UnlockItems(ctx, req); err != nil {
+			logrus.
+				WithError(err).
+				WithFields(fields).
+				WithField("owner_id", req.OwnerID).
+				Error("error unlocking items")
+		}
+	}
+}
+
+func (s *service) publish(topic string, event *core.Event, fields logrus.Fields) {
+	if s.producer == nil || topic == "" {
+		return
+	}
+
+	if _, err := s.producer.Publish(topic, event); err != nil {
+		logrus.WithError(err).WithFields(fields).WithField("topic", topic).Error("error publishing event")
+	}
+}
+
+// notify hands a finished trade to the transition handler, failures are only logged
+func (s *service) notify(ctx context.Context, trade *TradeOffer, outcome TradeOutcome, fields logrus.Fields) {
+	if s.transitions == nil {
+		return
+	}
+
+	transition := &Transition{
+		TradeID:        trade.ID,
+		OwnerID:        trade.OwnerID,
+		CounterpartyID: trade.WantedItemsOwnerID,
+		Outcome:        outcome,
+		OccurredAt:     time.Now(),
+	}
+
+	if err := s.transitions.HandleTransition(ctx, transition); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error handling trade transition")
+	}
+}
+
+// close finishes an offer that was not traded and releases its locks
+func (s *service) close(ctx context.Context, trade *TradeOffer, status TradeStatus, fields logrus.Fields) error {
+
+	from := trade.Status
+	trade.UpdateStatus(status)
+
+	updated, err := s.repository.Replace(ctx, trade, from, trade.Revision)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error updating trade")
+		return err
+	}
+
+	if !updated {
+		logrus.
+			WithError(core.ErrTradeInvalidStatus).
+			WithFields(fields).
+			Error("trade changed while being closed")
+
+		return core.ErrTradeInvalidStatus
+	}
+
+	logrus.WithFields(fields).Infof("trade status set to %s", strings.ToLower(string(status)))
+
+	// drafts and templates never locked anything
+	if from == TradeDraft || from == TradeTemplate {
+		return nil
+	}
+
+	// invalidated offers were closed by the inventory, not by a participant
+	switch status {
+	case TradeCancelled:
+		s.notify(ctx, trade, OutcomeCancelled, fields)
+	case TradeDeclined:
+		s.notify(ctx, trade, OutcomeDeclined, fields)
+	}
+
+	if err := s.inventoryService.UnlockItems(ctx, &inventory.UnlockItemsRequest{LockedBy: trade.ID}); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error unlocking items")
+	}
+
+	return nil
+}
+
+// getOwned returns the trade only to its owner
+func (s *service) getOwned(ctx context.Context, userID, id string, fields logrus.Fields) (*TradeOffer, error) {
+
+	trade, err := s.repository.GetByID(ctx, userID, id)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trade")
+		return nil, err
+	}
+
+	if trade.OwnerID != userID {
+		logrus.WithError(core.ErrForbidden).WithFields(fields).Error("only the owner can change the trade")
+		return nil, core.ErrForbidden
+	}
+
+	return trade, nil
+}
+
+// matches false when the offer changed since the revision the user viewed
+func (req *AcceptTradeOfferRequest) matches(trade *TradeOffer) bool {
+	// a pointer since 0 is a valid revision of offers stored before it existed
+	if req.ExpectedRevision != nil && *req.ExpectedRevision != trade.Revision {
+		return false
+	}
+
+	if req.ExpectedContentHash != "" && req.ExpectedContentHash != trade.ContentHash() {
+		return false
+	}
+
+	return true
+}
+
+func newLockItemsRequest(trade *TradeOffer) *inventory.LockItemsRequest {
+	lockItemsReq := &inventory.LockItemsRequest{
+		LockedBy:           trade.ID,
+		OwnerID:            trade.OwnerID,
+		WantedItemsOwnerID: trade.WantedItemsOwnerID,
+		OfferedItems:       make([]*inventory.ItemToLock, len(trade.OfferedItems)),
+		WantedItems:        make([]*inventory.ItemToLock, len(trade.WantedItems)),
+		OfferedCurrency:    trade.OfferedCurrency,
+		WantedCurrency:     trade.WantedCurrency,
+	}
+
+	for i, item := range trade.OfferedItems {
+		lockItemsReq.OfferedItems[i] = &inventory.ItemToLock{
+			ID:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	for i, item := range trade.WantedItems {
+		lockItemsReq.WantedItems[i] = &inventory.ItemToLock{
+			ID:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return lockItemsReq
+}
+
+func toItemsToLock(items []*Item) []*inventory.ItemToLock {
+	itemsToLock := make([]*inventory.ItemToLock, len(items))
+
+	for i, item := range items {
+		itemsToLock[i] = &inventory.ItemToLock{
+			ID:       item.ID,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return itemsToLock
+}
+
+func newTradeItemsRequest(trade *TradeOffer) *inventory.TradeItemsRequest {
+	return &inventory.TradeItemsRequest{
+		TradeID:            trade.ID,
+		OwnerID:            trade.OwnerID,
+		WantedItemsOwnerID: trade.WantedItemsOwnerID,
+		OfferedItems:       toItemsToTrade(trade.OfferedItems),
+		WantedItems:        toItemsToTrade(trade.WantedItems),
+		OfferedCurrency:    trade.OfferedCurrency,
+		WantedCurrency:     trade.WantedCurrency,
+	}
 }
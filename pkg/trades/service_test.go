@@ -109,6 +109,196 @@ func (s *serviceTestSuite) TestCreateLockFailed() {
 	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
 }
 
+func (s *serviceTestSuite) TestCreateWithCurrency() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedCurrency:    150,
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+	}
+
+	s.repository.On("Insert").Return(nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.NoError(err)
+	s.assert.NotNil(res)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+}
+
+func (s *serviceTestSuite) TestCreateCurrencyOnBothSides() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedCurrency:    150,
+		WantedCurrency:     100,
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+	}
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+	s.assert.Nil(res)
+
+	verr := err.(*core.Error)
+	s.assert.Len(verr.Violations, 1)
+	s.assert.Equal("wanted_currency", verr.Violations[0].Path)
+	s.assert.Equal(trades.RuleOneSided, verr.Violations[0].Rule)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *serviceTestSuite) TestCreateValidationFailed() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+			{
+				ID:       uuid.NewString(),
+				Quantity: 0,
+			},
+		},
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       "",
+				Quantity: 5,
+			},
+		},
+	}
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+	s.assert.Nil(res)
+
+	verr := err.(*core.Error)
+	s.assert.Len(verr.Violations, 2)
+	s.assert.Equal("offered_items[1].quantity", verr.Violations[0].Path)
+	s.assert.Equal(core.RuleMin, verr.Violations[0].Rule)
+	s.assert.Equal("wanted_items[0].id", verr.Violations[1].Path)
+	s.assert.Equal(core.RuleRequired, verr.Violations[1].Rule)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestCreateSelfOffer() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: userID,
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+	}
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.ErrorIs(err, core.ErrTradeSelfOffer)
+	s.assert.Nil(res)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *serviceTestSuite) TestCreateItemOfferedAndWanted() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+	itemID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       itemID,
+				Quantity: 5,
+			},
+		},
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       itemID,
+				Quantity: 1,
+			},
+		},
+	}
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.ErrorIs(err, core.ErrTradeItemOfferedAndWanted)
+	s.assert.Nil(res)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
+func (s *serviceTestSuite) TestCreateOpenOffersLimitExceeded() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	service := trades.NewService(
+		s.repository,
+		s.inventoryService,
+		trades.WithRuleEngine(trades.NewDefaultRuleEngine(s.repository, &core.TradeRules{MaxOpenOffers: 2})),
+	)
+
+	req := &trades.CreateTradeOfferRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+	}
+
+	s.repository.On("Count").Return(int64(2), nil)
+
+	res, err := service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.ErrorIs(err, core.ErrTradeOpenOffersLimitExceeded)
+	s.assert.Nil(res)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Count", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 0)
+}
+
 func (s *serviceTestSuite) TestAccept() {
 	correlationID := uuid.NewString()
 
@@ -132,18 +322,37 @@ func (s *serviceTestSuite) TestAccept() {
 	}
 
 	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
 	s.repository.On("Update").Return(nil)
 	s.inventoryService.On("TradesItems").Return(nil)
 
-	err := s.service.Accept(s.ctx, trade.OwnerID, correlationID, trade.ID)
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, correlationID, trade.ID, nil)
 
 	s.assert.NoError(err)
 
 	s.repository.AssertNumberOfCalls(s.T(), "GetByID", 1)
-	s.repository.AssertNumberOfCalls(s.T(), "Update", 2)
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Update", 1)
 	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 1)
 }
 
+func (s *serviceTestSuite) TestAcceptNotCounterparty() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	for _, userID := range []string{trade.OwnerID, uuid.NewString()} {
+		err := s.service.Accept(s.ctx, userID, uuid.NewString(), trade.ID, nil)
+
+		s.assert.ErrorIs(err, core.ErrForbidden)
+	}
+
+	s.assert.Equal(trades.TradePending, trade.Status)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
 func (s *serviceTestSuite) TestAcceptInvalidStatus() {
 	correlationID := uuid.NewString()
 
@@ -168,7 +377,7 @@ func (s *serviceTestSuite) TestAcceptInvalidStatus() {
 
 	s.repository.On("GetByID", trade.ID).Return(trade)
 
-	err := s.service.Accept(s.ctx, trade.OwnerID, correlationID, trade.ID)
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, correlationID, trade.ID, nil)
 
 	s.assert.ErrorIs(core.ErrTradeInvalidStatus, err)
 
@@ -198,14 +407,631 @@ func (s *serviceTestSuite) TestAcceptTradeItemsFailed() {
 	}
 
 	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
 	s.repository.On("Update").Return(nil)
 	s.inventoryService.On("TradesItems").Return(errors.New("unable-to-trade-items"))
 
-	err := s.service.Accept(s.ctx, trade.OwnerID, correlationID, trade.ID)
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, correlationID, trade.ID, nil)
 
 	s.assert.ErrorIs(core.ErrItemsTradeFailed, err)
 
 	s.repository.AssertNumberOfCalls(s.T(), "GetByID", 1)
-	s.repository.AssertNumberOfCalls(s.T(), "Update", 2)
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Update", 1)
 	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 1)
 }
+
+func (s *serviceTestSuite) TestCreateListing() {
+	userID := uuid.NewString()
+	correlationID := uuid.NewString()
+
+	req := &trades.CreateTradeOfferRequest{
+		Public: true,
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 5,
+			},
+		},
+	}
+
+	s.repository.On("Insert").Return(nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Create(s.ctx, userID, correlationID, req)
+
+	s.assert.NoError(err)
+	s.assert.NotNil(res)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Update", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+}
+
+func (s *serviceTestSuite) TestClaim() {
+	correlationID := uuid.NewString()
+	userID := uuid.NewString()
+
+	trade := newListedTrade()
+
+	claimed := *trade
+	claimed.WantedItemsOwnerID = userID
+	claimed.Status = trades.TradeAccepted
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Claim", userID, trade.ID).Return(&claimed)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+	s.inventoryService.On("TradesItems").Return(nil)
+
+	err := s.service.Claim(s.ctx, userID, correlationID, trade.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCompleted, claimed.Status)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Claim", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 1)
+}
+
+func (s *serviceTestSuite) TestClaimAlreadyClaimed() {
+	correlationID := uuid.NewString()
+	userID := uuid.NewString()
+
+	trade := newListedTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Claim", userID, trade.ID).Return(nil, core.ErrNotFound)
+
+	err := s.service.Claim(s.ctx, userID, correlationID, trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrTradeAlreadyClaimed)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestClaimLockFailed() {
+	correlationID := uuid.NewString()
+	userID := uuid.NewString()
+
+	trade := newListedTrade()
+
+	claimed := *trade
+	claimed.WantedItemsOwnerID = userID
+	claimed.Status = trades.TradeAccepted
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Claim", userID, trade.ID).Return(&claimed)
+	s.repository.On("Replace", trades.TradeAccepted).Return(true, nil)
+	s.inventoryService.On("LockItems").Return(errors.New("not-enought-items"))
+
+	err := s.service.Claim(s.ctx, userID, correlationID, trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrLockFailed)
+	s.assert.Equal(trades.TradeListed, claimed.Status)
+	s.assert.Empty(claimed.WantedItemsOwnerID)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "Update", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func (s *serviceTestSuite) TestCreateDraft() {
+	req := &trades.CreateTradeOfferRequest{
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+		Draft: true,
+	}
+
+	s.repository.On("Insert").Return(nil)
+
+	res, err := s.service.Create(s.ctx, uuid.NewString(), uuid.NewString(), req)
+
+	s.assert.NoError(err)
+	s.assert.NotEmpty(res.ID)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestUpdateDraft() {
+	trade := newDraftTrade()
+	wantedItemsOwnerID := uuid.NewString()
+
+	req := &trades.UpdateTradeOfferRequest{
+		WantedItemsOwnerID: &wantedItemsOwnerID,
+		WantedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 3,
+			},
+		},
+	}
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradeDraft).Return(true, nil)
+
+	err := s.service.Update(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, req)
+
+	s.assert.NoError(err)
+	s.assert.Equal(wantedItemsOwnerID, trade.WantedItemsOwnerID)
+	s.assert.Len(trade.OfferedItems, 1)
+	s.assert.Equal(int64(3), trade.WantedItems[0].Quantity)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 1)
+}
+
+func (s *serviceTestSuite) TestUpdateNotOwner() {
+	trade := newDraftTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Update(s.ctx, uuid.NewString(), uuid.NewString(), trade.ID, &trades.UpdateTradeOfferRequest{})
+
+	s.assert.ErrorIs(err, core.ErrForbidden)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+}
+
+func (s *serviceTestSuite) TestSubmitDraft() {
+	trade := newDraftTrade()
+	trade.WantedItemsOwnerID = uuid.NewString()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradeDraft).Return(true, nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	err := s.service.Submit(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradePending, trade.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+}
+
+func (s *serviceTestSuite) TestSubmitIncompleteDraft() {
+	trade := newDraftTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Submit(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestSubmitAlreadySubmitted() {
+	trade := newDraftTrade()
+	trade.WantedItemsOwnerID = uuid.NewString()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradeDraft).Return(false, nil)
+
+	err := s.service.Submit(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrTradeInvalidStatus)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestInstantiateTemplate() {
+	template := newDraftTrade()
+	template.Status = trades.TradeTemplate
+
+	s.repository.On("GetByID", template.ID).Return(template)
+	s.repository.On("Insert").Return(nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("LockItems").Return(nil)
+
+	res, err := s.service.Instantiate(s.ctx, template.OwnerID, uuid.NewString(), template.ID, &trades.InstantiateTemplateRequest{
+		WantedItemsOwnerID: uuid.NewString(),
+	})
+
+	s.assert.NoError(err)
+	s.assert.NotEqual(template.ID, res.ID)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Insert", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+}
+
+func (s *serviceTestSuite) TestGetByIDDraftOfAnotherUser() {
+	trade := newDraftTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	res, err := s.service.GetByID(s.ctx, uuid.NewString(), trade.ID)
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrNotFound)
+}
+
+func (s *serviceTestSuite) TestUpdatePending() {
+	trade := newPendingTrade()
+	producer := mock.NewProducer().(*mock.ProducerMock)
+	service := trades.NewService(s.repository, s.inventoryService, trades.WithProducer(producer, &core.Topics{TradeUpdated: "trade-updated"}))
+
+	req := &trades.UpdateTradeOfferRequest{
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+	}
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("LockItems").Return(nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+	producer.On("Publish", "trade-updated", trades.TradeOfferUpdatedEventType).Return(uuid.NewString(), nil)
+
+	err := service.Update(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, req)
+
+	s.assert.NoError(err)
+	s.assert.Equal(int64(2), trade.Revision)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 1)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+	producer.AssertNumberOfCalls(s.T(), "Publish", 1)
+}
+
+func (s *serviceTestSuite) TestUpdatePendingLockFailed() {
+	trade := newPendingTrade()
+
+	req := &trades.UpdateTradeOfferRequest{
+		OfferedItems: []*trades.ItemModel{
+			{
+				ID:       trade.OfferedItems[0].ID,
+				Quantity: 10,
+			},
+		},
+	}
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.inventoryService.On("LockItems").Return(errors.New("not-enought-items"))
+
+	err := s.service.Update(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, req)
+
+	s.assert.ErrorIs(err, core.ErrLockFailed)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 0)
+}
+
+func (s *serviceTestSuite) TestUpdatePendingAcceptedMeanwhile() {
+	trade := newPendingTrade()
+	offeredCurrency := int64(50)
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(false, nil)
+	s.inventoryService.On("LockItems").Return(nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+
+	err := s.service.Update(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, &trades.UpdateTradeOfferRequest{
+		OfferedCurrency: &offeredCurrency,
+	})
+
+	s.assert.ErrorIs(err, core.ErrTradeInvalidStatus)
+
+	// the added currency is released again
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *serviceTestSuite) TestUpdatePendingCounterparty() {
+	trade := newPendingTrade()
+	wantedItemsOwnerID := uuid.NewString()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Update(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID, &trades.UpdateTradeOfferRequest{
+		WantedItemsOwnerID: &wantedItemsOwnerID,
+	})
+
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+}
+
+func (s *serviceTestSuite) TestAcceptStaleRevision() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(false, nil)
+
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID, nil)
+
+	s.assert.ErrorIs(err, core.ErrTradeInvalidStatus)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func (s *serviceTestSuite) TestAcceptExpectedContentHash() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("TradesItems").Return(nil)
+
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID, &trades.AcceptTradeOfferRequest{
+		ExpectedContentHash: trade.ContentHash(),
+		ExpectedRevision:    &trade.Revision,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCompleted, trade.Status)
+}
+
+func (s *serviceTestSuite) TestAcceptContentChanged() {
+	trade := newPendingTrade()
+	expectedContentHash := trade.ContentHash()
+
+	trade.OfferedItems[0].Quantity++
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID, &trades.AcceptTradeOfferRequest{
+		ExpectedContentHash: expectedContentHash,
+	})
+
+	s.assert.ErrorIs(err, core.ErrTradeChanged)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func (s *serviceTestSuite) TestAcceptExpectedRevisionZero() {
+	trade := newPendingTrade()
+	trade.Revision = 1
+
+	expectedRevision := int64(0)
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Accept(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID, &trades.AcceptTradeOfferRequest{
+		ExpectedRevision: &expectedRevision,
+	})
+
+	s.assert.ErrorIs(err, core.ErrTradeChanged)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func (s *serviceTestSuite) TestContentHashIgnoresItemsOrder() {
+	trade := newPendingTrade()
+	trade.OfferedItems = append(trade.OfferedItems, &trades.Item{ID: uuid.NewString(), Quantity: 4})
+
+	reordered := *trade
+	reordered.OfferedItems = []*trades.Item{trade.OfferedItems[1], trade.OfferedItems[0]}
+
+	s.assert.Equal(trade.ContentHash(), reordered.ContentHash())
+
+	reordered.WantedCurrency = 10
+
+	s.assert.NotEqual(trade.ContentHash(), reordered.ContentHash())
+}
+
+func (s *serviceTestSuite) TestCancel() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+
+	err := s.service.Cancel(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCancelled, trade.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *serviceTestSuite) TestCancelTemplate() {
+	template := newDraftTrade()
+	template.Status = trades.TradeTemplate
+
+	s.repository.On("GetByID", template.ID).Return(template)
+	s.repository.On("Replace", trades.TradeTemplate).Return(true, nil)
+
+	err := s.service.Cancel(s.ctx, template.OwnerID, uuid.NewString(), template.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCancelled, template.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 0)
+}
+
+func (s *serviceTestSuite) TestCancelNotOwner() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Cancel(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrForbidden)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+}
+
+func (s *serviceTestSuite) TestDecline() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+
+	err := s.service.Decline(s.ctx, trade.WantedItemsOwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeDeclined, trade.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *serviceTestSuite) TestDeclineNotCounterparty() {
+	trade := newPendingTrade()
+
+	s.repository.On("GetByID", trade.ID).Return(trade)
+
+	err := s.service.Decline(s.ctx, trade.OwnerID, uuid.NewString(), trade.ID)
+
+	s.assert.ErrorIs(err, core.ErrForbidden)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 0)
+}
+
+func (s *serviceTestSuite) TestInvalidateItemRemoved() {
+	trade := newPendingTrade()
+	item := trade.OfferedItems[0]
+
+	s.repository.On("GetByItem", item.ID).Return(&trades.ResultTradeOffers{Trades: []*trades.TradeOffer{trade}})
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+
+	err := s.service.InvalidateItem(s.ctx, uuid.NewString(), &trades.ItemChange{
+		ItemID:  item.ID,
+		OwnerID: trade.OwnerID,
+		Removed: true,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeInvalidated, trade.Status)
+	s.assert.Equal(trades.ReasonItemRemoved, trade.Reason)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "UnlockItems", 1)
+}
+
+func (s *serviceTestSuite) TestInvalidateItemQuantityStillEnough() {
+	trade := newPendingTrade()
+	item := trade.OfferedItems[0]
+
+	s.repository.On("GetByItem", item.ID).Return(&trades.ResultTradeOffers{Trades: []*trades.TradeOffer{trade}})
+
+	err := s.service.InvalidateItem(s.ctx, uuid.NewString(), &trades.ItemChange{
+		ItemID:   item.ID,
+		OwnerID:  trade.OwnerID,
+		Quantity: item.Quantity,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradePending, trade.Status)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Replace", 0)
+}
+
+func (s *serviceTestSuite) TestInvalidateItemQuantityDropped() {
+	trade := newPendingTrade()
+	item := trade.WantedItems[0]
+
+	s.repository.On("GetByItem", item.ID).Return(&trades.ResultTradeOffers{Trades: []*trades.TradeOffer{trade}})
+	s.repository.On("Replace", trades.TradePending).Return(true, nil)
+	s.inventoryService.On("UnlockItems", trade.ID).Return(nil)
+
+	err := s.service.InvalidateItem(s.ctx, uuid.NewString(), &trades.ItemChange{
+		ItemID:   item.ID,
+		OwnerID:  trade.WantedItemsOwnerID,
+		Quantity: item.Quantity - 1,
+	})
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeInvalidated, trade.Status)
+	s.assert.Equal(trades.ReasonItemQuantityDropped, trade.Reason)
+}
+
+func (s *serviceTestSuite) TestTradeLockedSkipsLocking() {
+	trade := newPendingTrade()
+	trade.Status = trades.TradeCreated
+
+	s.repository.On("Insert").Return(nil)
+	s.repository.On("Update").Return(nil)
+	s.inventoryService.On("TradesItems").Return(nil)
+
+	err := s.service.TradeLocked(s.ctx, uuid.NewString(), trade)
+
+	s.assert.NoError(err)
+	s.assert.Equal(trades.TradeCompleted, trade.Status)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "LockItems", 0)
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 1)
+}
+
+func (s *serviceTestSuite) TestTradeLockedAlreadyCompleted() {
+	trade := newPendingTrade()
+
+	existing := *trade
+	existing.Status = trades.TradeCompleted
+
+	s.repository.On("Insert").Return(errors.New("duplicate key"))
+	s.repository.On("GetByID", trade.ID).Return(&existing)
+
+	err := s.service.TradeLocked(s.ctx, uuid.NewString(), trade)
+
+	s.assert.NoError(err)
+
+	s.inventoryService.AssertNumberOfCalls(s.T(), "TradesItems", 0)
+}
+
+func newPendingTrade() *trades.TradeOffer {
+	trade := newDraftTrade()
+	trade.WantedItemsOwnerID = uuid.NewString()
+	trade.Status = trades.TradePending
+	trade.Revision = 1
+
+	return trade
+}
+
+func newDraftTrade() *trades.TradeOffer {
+	return &trades.TradeOffer{
+		ID:      uuid.NewString(),
+		OwnerID: uuid.NewString(),
+		Status:  trades.TradeDraft,
+		OfferedItems: []*trades.Item{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+		WantedItems: []*trades.Item{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 2,
+			},
+		},
+	}
+}
+
+func newListedTrade() *trades.TradeOffer {
+	return &trades.TradeOffer{
+		ID:      uuid.NewString(),
+		OwnerID: uuid.NewString(),
+		Status:  trades.TradeListed,
+		Public:  true,
+		OfferedItems: []*trades.Item{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 1,
+			},
+		},
+		WantedItems: []*trades.Item{
+			{
+				ID:       uuid.NewString(),
+				Quantity: 2,
+			},
+		},
+	}
+}
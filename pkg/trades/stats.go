@@ -0,0 +1,52 @@
+package trades
+
+import (
+	"context"
+	"time"
+)
+
+// GetTradeStats optional range applied to the offers creation date
+type GetTradeStats struct {
+	From     *time.Time
+	To       *time.Time
+	TopItems int64
+}
+
+// ItemStats ...
+type ItemStats struct {
+	ID       string `bson:"_id"`
+	Trades   int64  `bson:"trades"`
+	Quantity int64  `bson:"quantity"`
+}
+
+// TradeStats summary of the offers a user sent and received
+type TradeStats struct {
+	Sent             map[TradeStatus]int64
+	Received         map[TradeStatus]int64
+	MedianCompletion time.Duration
+	TopItems         []*ItemStats
+}
+
+// StatsService ...
+type StatsService interface {
+	Get(ctx context.Context, userID string, req *GetTradeStatsRequest) (*GetTradeStatsResponse, error)
+}
+
+// CompletionRate completed offers over every finished one, zero when none finished
+func (stats *TradeStats) CompletionRate() float64 {
+	var completed, finished int64
+
+	for _, counts := range []map[TradeStatus]int64{stats.Sent, stats.Received} {
+		completed += counts[TradeCompleted]
+
+		for _, status := range FinishedTradeStatuses {
+			finished += counts[status]
+		}
+	}
+
+	if finished == 0 {
+		return 0
+	}
+
+	return float64(completed) / float64(finished)
+}
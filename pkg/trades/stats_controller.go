@@ -0,0 +1,61 @@
+package trades
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/gin-gonic/gin"
+)
+
+// StatsController ...
+type StatsController struct {
+	authenticate *core.Authenticate
+	service      StatsService
+	maxAge       time.Duration
+}
+
+// NewStatsController maxAge is sent to clients in the Cache-Control header
+func NewStatsController(authenticate *core.Authenticate, service StatsService, maxAge time.Duration) StatsController {
+	return StatsController{
+		authenticate: authenticate,
+		service:      service,
+		maxAge:       maxAge,
+	}
+}
+
+// RegisterRoutes ...
+func (c *StatsController) RegisterRoutes(r *gin.RouterGroup) {
+	stats := r.Group("/trades/stats")
+	{
+		stats.Use(
+			c.authenticate.Middleware(),
+		)
+
+		stats.GET("", c.get)
+	}
+}
+
+func (c *StatsController) get(ctx *gin.Context) {
+	req := new(GetTradeStatsRequest)
+	userID := ctx.GetString("user_id")
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		core.HandleRestError(ctx, core.ErrMalformedJSON)
+		return
+	}
+
+	res, err := c.service.Get(ctx, userID, req)
+
+	if err != nil {
+		core.HandleRestError(ctx, err)
+		return
+	}
+
+	if c.maxAge > 0 {
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int64(c.maxAge.Seconds())))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,60 @@
+package trades
+
+import "time"
+
+// GetTradeStatsRequest ...
+type GetTradeStatsRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ItemStatsModel ...
+type ItemStatsModel struct {
+	ID       string `json:"id"`
+	Trades   int64  `json:"trades"`
+	Quantity int64  `json:"quantity"`
+}
+
+// GetTradeStatsResponse ...
+type GetTradeStatsResponse struct {
+	Sent                    map[string]int64  `json:"sent"`
+	Received                map[string]int64  `json:"received"`
+	CompletionRate          float64           `json:"completion_rate"`
+	MedianCompletionSeconds float64           `json:"median_completion_seconds"`
+	TopItems                []*ItemStatsModel `json:"top_items"`
+	From                    *time.Time        `json:"from"`
+	To                      *time.Time        `json:"to"`
+}
+
+// ParseGetTradeStatsResponse ...
+func ParseGetTradeStatsResponse(req *GetTradeStatsRequest, stats *TradeStats) *GetTradeStatsResponse {
+	res := &GetTradeStatsResponse{
+		Sent:                    parseStatusCounts(stats.Sent),
+		Received:                parseStatusCounts(stats.Received),
+		CompletionRate:          stats.CompletionRate(),
+		MedianCompletionSeconds: stats.MedianCompletion.Seconds(),
+		TopItems:                make([]*ItemStatsModel, len(stats.TopItems)),
+		From:                    req.From,
+		To:                      req.To,
+	}
+
+	for i, item := range stats.TopItems {
+		res.TopItems[i] = &ItemStatsModel{
+			ID:       item.ID,
+			Trades:   item.Trades,
+			Quantity: item.Quantity,
+		}
+	}
+
+	return res
+}
+
+func parseStatusCounts(counts map[TradeStatus]int64) map[string]int64 {
+	result := make(map[string]int64, len(counts))
+
+	for status, count := range counts {
+		result[string(status)] = count
+	}
+
+	return result
+}
@@ -0,0 +1,150 @@
+package trades
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/sirupsen/logrus"
+)
+
+type cachedStats struct {
+	key       string
+	stats     *TradeStats
+	expiresAt time.Time
+}
+
+type statsService struct {
+	repository Repository
+	settings   *core.Stats
+
+	mu    sync.Mutex
+	cache map[string]*cachedStats
+	// order entries by when they were stored, with a single ttl it is also
+	// the order they expire in
+	order []*cachedStats
+}
+
+// NewStatsService results are cached in memory for settings.CacheTTL
+func NewStatsService(repository Repository, settings *core.Stats) StatsService {
+
+	// copied so the defaults are not written into the shared settings
+	conf := core.Stats{}
+	if settings != nil {
+		conf = *settings
+	}
+
+	if conf.TopItems < 1 {
+		conf.TopItems = 10
+	}
+
+	if conf.CacheSize < 1 {
+		conf.CacheSize = 10000
+	}
+
+	return &statsService{
+		repository: repository,
+		settings:   &conf,
+		cache:      map[string]*cachedStats{},
+	}
+}
+
+func (s *statsService) Get(ctx context.Context, userID string, req *GetTradeStatsRequest) (*GetTradeStatsResponse, error) {
+
+	fields := logrus.Fields{
+		"user_id": userID,
+		"from":    req.From,
+		"to":      req.To,
+	}
+
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		validation := core.NewValidation()
+		validation.Add("to", core.RuleMin, "to can't be before from")
+
+		err := validation.Err()
+		logrus.WithError(err).WithFields(fields).Error("invalid stats range")
+
+		return nil, err
+	}
+
+	key := statsCacheKey(userID, req)
+
+	if stats := s.cached(key); stats != nil {
+		return ParseGetTradeStatsResponse(req, stats), nil
+	}
+
+	stats, err := s.repository.Stats(ctx, userID, &GetTradeStats{
+		From:     req.From,
+		To:       req.To,
+		TopItems: s.settings.TopItems,
+	})
+
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Error("error getting trade stats")
+		return nil, err
+	}
+
+	s.store(key, stats)
+
+	return ParseGetTradeStatsResponse(req, stats), nil
+}
+
+func (s *statsService) cached(key string) *TradeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.cache[key]
+	if !exists || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+
+	return entry.stats
+}
+
+func (s *statsService) store(key string, stats *TradeStats) {
+	if s.settings.CacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	entry := &cachedStats{
+		key:       key,
+		stats:     stats,
+		expiresAt: now.Add(s.settings.CacheTTL),
+	}
+
+	s.cache[key] = entry
+	s.order = append(s.order, entry)
+
+	// only the oldest entries are checked, they are the expired ones and the
+	// first to go when clients request more ranges than the cache holds
+	for len(s.order) > 0 && (len(s.cache) > s.settings.CacheSize || now.After(s.order[0].expiresAt)) {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+
+		// replaced by a newer entry for the same key
+		if s.cache[oldest.key] == oldest {
+			delete(s.cache, oldest.key)
+		}
+	}
+}
+
+func statsCacheKey(userID string, req *GetTradeStatsRequest) string {
+	var from, to int64
+
+	if req.From != nil {
+		from = req.From.UnixNano()
+	}
+
+	if req.To != nil {
+		to = req.To.UnixNano()
+	}
+
+	return fmt.Sprintf("%s:%d:%d", userID, from, to)
+}
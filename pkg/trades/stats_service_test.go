@@ -0,0 +1,99 @@
+package trades_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/d-leme/tradew-trades/pkg/core"
+	"github.com/d-leme/tradew-trades/pkg/trades"
+	"github.com/d-leme/tradew-trades/pkg/trades/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type statsServiceTestSuite struct {
+	suite.Suite
+	assert     *assert.Assertions
+	ctx        context.Context
+	repository *mock.RepositoryMock
+	service    trades.StatsService
+}
+
+func TestStatsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(statsServiceTestSuite))
+}
+
+func (s *statsServiceTestSuite) SetupSuite() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+}
+
+func (s *statsServiceTestSuite) SetupTest() {
+	s.repository = mock.NewRepository().(*mock.RepositoryMock)
+	s.service = trades.NewStatsService(s.repository, &core.Stats{CacheTTL: time.Minute})
+}
+
+func (s *statsServiceTestSuite) TestGetCached() {
+	userID := uuid.NewString()
+
+	s.repository.On("Stats", userID).Return(&trades.TradeStats{
+		Sent:             map[trades.TradeStatus]int64{trades.TradeCompleted: 1, trades.TradeError: 1},
+		Received:         map[trades.TradeStatus]int64{},
+		MedianCompletion: time.Minute,
+		TopItems:         []*trades.ItemStats{{ID: "sword", Trades: 1, Quantity: 2}},
+	})
+
+	for i := 0; i < 2; i++ {
+		res, err := s.service.Get(s.ctx, userID, &trades.GetTradeStatsRequest{})
+
+		s.assert.NoError(err)
+		s.assert.Equal(int64(1), res.Sent[string(trades.TradeCompleted)])
+		s.assert.Equal(0.5, res.CompletionRate)
+		s.assert.Equal(float64(60), res.MedianCompletionSeconds)
+		s.assert.Equal("sword", res.TopItems[0].ID)
+	}
+
+	s.repository.AssertNumberOfCalls(s.T(), "Stats", 1)
+}
+
+func (s *statsServiceTestSuite) TestGetInvalidRange() {
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	res, err := s.service.Get(s.ctx, uuid.NewString(), &trades.GetTradeStatsRequest{From: &from, To: &to})
+
+	s.assert.Nil(res)
+	s.assert.ErrorIs(err, core.ErrValidationFailed)
+
+	s.repository.AssertNumberOfCalls(s.T(), "Stats", 0)
+}
+
+func (s *statsServiceTestSuite) TestDefaultsKeepSettings() {
+	settings := &core.Stats{}
+
+	trades.NewStatsService(s.repository, settings)
+
+	s.assert.Equal(int64(0), settings.TopItems)
+}
+
+func (s *statsServiceTestSuite) TestGetEvictsOldestEntry() {
+	service := trades.NewStatsService(s.repository, &core.Stats{CacheTTL: time.Minute, CacheSize: 2})
+	userIDs := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+
+	for _, userID := range userIDs {
+		s.repository.On("Stats", userID).Return(&trades.TradeStats{})
+
+		_, err := service.Get(s.ctx, userID, &trades.GetTradeStatsRequest{})
+		s.assert.NoError(err)
+	}
+
+	// the last two are still cached, the first one was dropped
+	for _, userID := range []string{userIDs[2], userIDs[1], userIDs[0]} {
+		_, err := service.Get(s.ctx, userID, &trades.GetTradeStatsRequest{})
+		s.assert.NoError(err)
+	}
+
+	s.repository.AssertNumberOfCalls(s.T(), "Stats", 4)
+}